@@ -11,11 +11,14 @@ import (
 	sync "sync"
 
 	proto "github.com/golang/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -29,15 +32,169 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type Blog_Status int32
+
+const (
+	Blog_UNSPECIFIED Blog_Status = 0
+	Blog_DRAFT       Blog_Status = 1 // every blog starts as a draft
+	Blog_IN_REVIEW   Blog_Status = 2
+	Blog_PUBLISHED   Blog_Status = 3
+	Blog_ARCHIVED    Blog_Status = 4
+)
+
+// Enum value maps for Blog_Status.
+var (
+	Blog_Status_name = map[int32]string{
+		0: "UNSPECIFIED",
+		1: "DRAFT",
+		2: "IN_REVIEW",
+		3: "PUBLISHED",
+		4: "ARCHIVED",
+	}
+	Blog_Status_value = map[string]int32{
+		"UNSPECIFIED": 0,
+		"DRAFT":       1,
+		"IN_REVIEW":   2,
+		"PUBLISHED":   3,
+		"ARCHIVED":    4,
+	}
+)
+
+func (x Blog_Status) Enum() *Blog_Status {
+	p := new(Blog_Status)
+	*p = x
+	return p
+}
+
+func (x Blog_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Blog_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_blog_proto_enumTypes[0].Descriptor()
+}
+
+func (Blog_Status) Type() protoreflect.EnumType {
+	return &file_proto_blog_proto_enumTypes[0]
+}
+
+func (x Blog_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Blog_Status.Descriptor instead.
+func (Blog_Status) EnumDescriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// fields the listing can be ordered by, every order is tie-broken by id
+type ListBlogReq_SortField int32
+
+const (
+	ListBlogReq_ID        ListBlogReq_SortField = 0 // default, which is also the creation order
+	ListBlogReq_TITLE     ListBlogReq_SortField = 1
+	ListBlogReq_AUTHOR_ID ListBlogReq_SortField = 2
+)
+
+// Enum value maps for ListBlogReq_SortField.
+var (
+	ListBlogReq_SortField_name = map[int32]string{
+		0: "ID",
+		1: "TITLE",
+		2: "AUTHOR_ID",
+	}
+	ListBlogReq_SortField_value = map[string]int32{
+		"ID":        0,
+		"TITLE":     1,
+		"AUTHOR_ID": 2,
+	}
+)
+
+func (x ListBlogReq_SortField) Enum() *ListBlogReq_SortField {
+	p := new(ListBlogReq_SortField)
+	*p = x
+	return p
+}
+
+func (x ListBlogReq_SortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListBlogReq_SortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_blog_proto_enumTypes[1].Descriptor()
+}
+
+func (ListBlogReq_SortField) Type() protoreflect.EnumType {
+	return &file_proto_blog_proto_enumTypes[1]
+}
+
+func (x ListBlogReq_SortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListBlogReq_SortField.Descriptor instead.
+func (ListBlogReq_SortField) EnumDescriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{27, 0}
+}
+
+type ListBlogReq_SortDirection int32
+
+const (
+	ListBlogReq_ASC  ListBlogReq_SortDirection = 0
+	ListBlogReq_DESC ListBlogReq_SortDirection = 1
+)
+
+// Enum value maps for ListBlogReq_SortDirection.
+var (
+	ListBlogReq_SortDirection_name = map[int32]string{
+		0: "ASC",
+		1: "DESC",
+	}
+	ListBlogReq_SortDirection_value = map[string]int32{
+		"ASC":  0,
+		"DESC": 1,
+	}
+)
+
+func (x ListBlogReq_SortDirection) Enum() *ListBlogReq_SortDirection {
+	p := new(ListBlogReq_SortDirection)
+	*p = x
+	return p
+}
+
+func (x ListBlogReq_SortDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListBlogReq_SortDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_blog_proto_enumTypes[2].Descriptor()
+}
+
+func (ListBlogReq_SortDirection) Type() protoreflect.EnumType {
+	return &file_proto_blog_proto_enumTypes[2]
+}
+
+func (x ListBlogReq_SortDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListBlogReq_SortDirection.Descriptor instead.
+func (ListBlogReq_SortDirection) EnumDescriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{27, 1}
+}
+
 type Blog struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	AuthorId string `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
-	Title    string `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
-	Content  string `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AuthorId  string                 `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Title     string                 `protobuf:"bytes,3,opt,name=title,proto3" json:"title,omitempty"`
+	Content   string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Version   int64                  `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`                     // set by the server, same as the latest revision number, grows with every change
+	Status    Blog_Status            `protobuf:"varint,6,opt,name=status,proto3,enum=blog.Blog_Status" json:"status,omitempty"` // set by the server, changed only through the workflow rpcs
+	PublishAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"` // set while the blog is IN_REVIEW with a scheduled publication
 }
 
 func (x *Blog) Reset() {
@@ -100,6 +257,91 @@ func (x *Blog) GetContent() string {
 	return ""
 }
 
+func (x *Blog) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Blog) GetStatus() Blog_Status {
+	if x != nil {
+		return x.Status
+	}
+	return Blog_UNSPECIFIED
+}
+
+func (x *Blog) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
+// attached to the details of the ABORTED status returned when an expected_version is stale
+type VersionMismatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	CurrentVersion  int64  `protobuf:"varint,3,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+}
+
+func (x *VersionMismatch) Reset() {
+	*x = VersionMismatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VersionMismatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionMismatch) ProtoMessage() {}
+
+func (x *VersionMismatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionMismatch.ProtoReflect.Descriptor instead.
+func (*VersionMismatch) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VersionMismatch) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VersionMismatch) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *VersionMismatch) GetCurrentVersion() int64 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
 // create, read and update will return a blog message
 type CreateBlogReq struct {
 	state         protoimpl.MessageState
@@ -112,7 +354,7 @@ type CreateBlogReq struct {
 func (x *CreateBlogReq) Reset() {
 	*x = CreateBlogReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[1]
+		mi := &file_proto_blog_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -125,7 +367,7 @@ func (x *CreateBlogReq) String() string {
 func (*CreateBlogReq) ProtoMessage() {}
 
 func (x *CreateBlogReq) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[1]
+	mi := &file_proto_blog_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -138,7 +380,7 @@ func (x *CreateBlogReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateBlogReq.ProtoReflect.Descriptor instead.
 func (*CreateBlogReq) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{1}
+	return file_proto_blog_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CreateBlogReq) GetBlog() *Blog {
@@ -159,7 +401,7 @@ type CreateBlogRes struct {
 func (x *CreateBlogRes) Reset() {
 	*x = CreateBlogRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[2]
+		mi := &file_proto_blog_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -172,7 +414,7 @@ func (x *CreateBlogRes) String() string {
 func (*CreateBlogRes) ProtoMessage() {}
 
 func (x *CreateBlogRes) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[2]
+	mi := &file_proto_blog_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -185,7 +427,7 @@ func (x *CreateBlogRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateBlogRes.ProtoReflect.Descriptor instead.
 func (*CreateBlogRes) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{2}
+	return file_proto_blog_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateBlogRes) GetBlog() *Blog {
@@ -195,19 +437,21 @@ func (x *CreateBlogRes) GetBlog() *Blog {
 	return nil
 }
 
-// blog will be searched using an id
+// blog will be searched using an id, at most one of revision and as_of can be set to read an older state
 type ReadBlogReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Revision int64                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`    // 0 for the latest revision
+	AsOf     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"` // the blog as it was at that time
 }
 
 func (x *ReadBlogReq) Reset() {
 	*x = ReadBlogReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[3]
+		mi := &file_proto_blog_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -220,7 +464,7 @@ func (x *ReadBlogReq) String() string {
 func (*ReadBlogReq) ProtoMessage() {}
 
 func (x *ReadBlogReq) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[3]
+	mi := &file_proto_blog_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -233,7 +477,7 @@ func (x *ReadBlogReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadBlogReq.ProtoReflect.Descriptor instead.
 func (*ReadBlogReq) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{3}
+	return file_proto_blog_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ReadBlogReq) GetId() string {
@@ -243,18 +487,33 @@ func (x *ReadBlogReq) GetId() string {
 	return ""
 }
 
+func (x *ReadBlogReq) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *ReadBlogReq) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
 type ReadBlogRes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Blog     *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Revision int64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // revision the blog was read at
 }
 
 func (x *ReadBlogRes) Reset() {
 	*x = ReadBlogRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[4]
+		mi := &file_proto_blog_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -267,7 +526,7 @@ func (x *ReadBlogRes) String() string {
 func (*ReadBlogRes) ProtoMessage() {}
 
 func (x *ReadBlogRes) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[4]
+	mi := &file_proto_blog_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -280,7 +539,7 @@ func (x *ReadBlogRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadBlogRes.ProtoReflect.Descriptor instead.
 func (*ReadBlogRes) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{4}
+	return file_proto_blog_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ReadBlogRes) GetBlog() *Blog {
@@ -290,19 +549,30 @@ func (x *ReadBlogRes) GetBlog() *Blog {
 	return nil
 }
 
+func (x *ReadBlogRes) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
 // same as create, but with id filled already
 type UpdateBlogReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Blog            *Blog  `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	EditorId        string `protobuf:"bytes,2,opt,name=editor_id,json=editorId,proto3" json:"editor_id,omitempty"`                       // who makes the change, recorded in the revision history, defaults to the author
+	ExpectedVersion int64  `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // if set, the update is rejected unless the blog is still at this version
+	// fields of blog to change, among author_id, title and content, all of them if empty
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 }
 
 func (x *UpdateBlogReq) Reset() {
 	*x = UpdateBlogReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[5]
+		mi := &file_proto_blog_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -315,7 +585,7 @@ func (x *UpdateBlogReq) String() string {
 func (*UpdateBlogReq) ProtoMessage() {}
 
 func (x *UpdateBlogReq) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[5]
+	mi := &file_proto_blog_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -328,7 +598,7 @@ func (x *UpdateBlogReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateBlogReq.ProtoReflect.Descriptor instead.
 func (*UpdateBlogReq) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{5}
+	return file_proto_blog_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateBlogReq) GetBlog() *Blog {
@@ -338,18 +608,40 @@ func (x *UpdateBlogReq) GetBlog() *Blog {
 	return nil
 }
 
+func (x *UpdateBlogReq) GetEditorId() string {
+	if x != nil {
+		return x.EditorId
+	}
+	return ""
+}
+
+func (x *UpdateBlogReq) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *UpdateBlogReq) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
 type UpdateBlogRes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Blog     *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Revision int64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // revision recorded for this update
 }
 
 func (x *UpdateBlogRes) Reset() {
 	*x = UpdateBlogRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[6]
+		mi := &file_proto_blog_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -362,7 +654,7 @@ func (x *UpdateBlogRes) String() string {
 func (*UpdateBlogRes) ProtoMessage() {}
 
 func (x *UpdateBlogRes) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[6]
+	mi := &file_proto_blog_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -375,7 +667,7 @@ func (x *UpdateBlogRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateBlogRes.ProtoReflect.Descriptor instead.
 func (*UpdateBlogRes) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{6}
+	return file_proto_blog_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UpdateBlogRes) GetBlog() *Blog {
@@ -385,18 +677,27 @@ func (x *UpdateBlogRes) GetBlog() *Blog {
 	return nil
 }
 
+func (x *UpdateBlogRes) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// deleting moves the blog to the trash, it is hidden from reads and listings until undeleted or purged
 type DeleteBlogReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // if set, the delete is rejected unless the blog is still at this version
 }
 
 func (x *DeleteBlogReq) Reset() {
 	*x = DeleteBlogReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[7]
+		mi := &file_proto_blog_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -409,7 +710,7 @@ func (x *DeleteBlogReq) String() string {
 func (*DeleteBlogReq) ProtoMessage() {}
 
 func (x *DeleteBlogReq) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[7]
+	mi := &file_proto_blog_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -422,7 +723,7 @@ func (x *DeleteBlogReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteBlogReq.ProtoReflect.Descriptor instead.
 func (*DeleteBlogReq) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{7}
+	return file_proto_blog_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DeleteBlogReq) GetId() string {
@@ -432,6 +733,13 @@ func (x *DeleteBlogReq) GetId() string {
 	return ""
 }
 
+func (x *DeleteBlogReq) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type DeleteBlogRes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -443,7 +751,7 @@ type DeleteBlogRes struct {
 func (x *DeleteBlogRes) Reset() {
 	*x = DeleteBlogRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[8]
+		mi := &file_proto_blog_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -456,7 +764,7 @@ func (x *DeleteBlogRes) String() string {
 func (*DeleteBlogRes) ProtoMessage() {}
 
 func (x *DeleteBlogRes) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[8]
+	mi := &file_proto_blog_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -469,7 +777,7 @@ func (x *DeleteBlogRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteBlogRes.ProtoReflect.Descriptor instead.
 func (*DeleteBlogRes) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{8}
+	return file_proto_blog_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *DeleteBlogRes) GetSuccess() bool {
@@ -479,30 +787,32 @@ func (x *DeleteBlogRes) GetSuccess() bool {
 	return false
 }
 
-// ListBlogs will use server-streaming
-type ListBlogReq struct {
+// brings a blog back from the trash
+type UndeleteBlogReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *ListBlogReq) Reset() {
-	*x = ListBlogReq{}
+func (x *UndeleteBlogReq) Reset() {
+	*x = UndeleteBlogReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[9]
+		mi := &file_proto_blog_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListBlogReq) String() string {
+func (x *UndeleteBlogReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListBlogReq) ProtoMessage() {}
+func (*UndeleteBlogReq) ProtoMessage() {}
 
-func (x *ListBlogReq) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[9]
+func (x *UndeleteBlogReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -513,12 +823,19 @@ func (x *ListBlogReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListBlogReq.ProtoReflect.Descriptor instead.
-func (*ListBlogReq) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use UndeleteBlogReq.ProtoReflect.Descriptor instead.
+func (*UndeleteBlogReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{10}
 }
 
-type ListBlogRes struct {
+func (x *UndeleteBlogReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type UndeleteBlogRes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -526,23 +843,23 @@ type ListBlogRes struct {
 	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
 }
 
-func (x *ListBlogRes) Reset() {
-	*x = ListBlogRes{}
+func (x *UndeleteBlogRes) Reset() {
+	*x = UndeleteBlogRes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_blog_proto_msgTypes[10]
+		mi := &file_proto_blog_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListBlogRes) String() string {
+func (x *UndeleteBlogRes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListBlogRes) ProtoMessage() {}
+func (*UndeleteBlogRes) ProtoMessage() {}
 
-func (x *ListBlogRes) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_blog_proto_msgTypes[10]
+func (x *UndeleteBlogRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -553,74 +870,1936 @@ func (x *ListBlogRes) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListBlogRes.ProtoReflect.Descriptor instead.
-func (*ListBlogRes) Descriptor() ([]byte, []int) {
-	return file_proto_blog_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use UndeleteBlogRes.ProtoReflect.Descriptor instead.
+func (*UndeleteBlogRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ListBlogRes) GetBlog() *Blog {
+func (x *UndeleteBlogRes) GetBlog() *Blog {
 	if x != nil {
 		return x.Blog
 	}
 	return nil
 }
 
-var File_proto_blog_proto protoreflect.FileDescriptor
+// permanently removes a blog from the trash, along with its revisions
+type PurgeBlogReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_proto_blog_proto_rawDesc = []byte{
-	0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22, 0x63, 0x0a, 0x04, 0x42, 0x6c, 0x6f, 0x67,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
-	0x12, 0x1b, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a,
-	0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69,
-	0x74, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x2f, 0x0a,
-	0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x12, 0x1e,
-	0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62,
-	0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22, 0x2f,
-	0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x12,
-	0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
-	0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22,
-	0x1d, 0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2d,
-	0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a,
-	0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x6c,
-	0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22, 0x2f, 0x0a,
-	0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x12, 0x1e,
-	0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62,
-	0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22, 0x2f,
-	0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x12,
-	0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
-	0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22,
-	0x1f, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
-	0x22, 0x29, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65,
-	0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x0d, 0x0a, 0x0b, 0x4c,
-	0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x22, 0x2d, 0x0a, 0x0b, 0x4c, 0x69,
-	0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f,
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *PurgeBlogReq) Reset() {
+	*x = PurgeBlogReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeBlogReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeBlogReq) ProtoMessage() {}
+
+func (x *PurgeBlogReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeBlogReq.ProtoReflect.Descriptor instead.
+func (*PurgeBlogReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PurgeBlogReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type PurgeBlogRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+}
+
+func (x *PurgeBlogRes) Reset() {
+	*x = PurgeBlogRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeBlogRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeBlogRes) ProtoMessage() {}
+
+func (x *PurgeBlogRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeBlogRes.ProtoReflect.Descriptor instead.
+func (*PurgeBlogRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PurgeBlogRes) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// streams the blogs in the trash, in creation order
+type ListDeletedBlogsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AuthorId string `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // only blogs of this author, blank for all authors
+}
+
+func (x *ListDeletedBlogsReq) Reset() {
+	*x = ListDeletedBlogsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeletedBlogsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeletedBlogsReq) ProtoMessage() {}
+
+func (x *ListDeletedBlogsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeletedBlogsReq.ProtoReflect.Descriptor instead.
+func (*ListDeletedBlogsReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListDeletedBlogsReq) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+type ListDeletedBlogsRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blog      *Blog                  `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+}
+
+func (x *ListDeletedBlogsRes) Reset() {
+	*x = ListDeletedBlogsRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeletedBlogsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeletedBlogsRes) ProtoMessage() {}
+
+func (x *ListDeletedBlogsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeletedBlogsRes.ProtoReflect.Descriptor instead.
+func (*ListDeletedBlogsRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListDeletedBlogsRes) GetBlog() *Blog {
+	if x != nil {
+		return x.Blog
+	}
+	return nil
+}
+
+func (x *ListDeletedBlogsRes) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+// the workflow rpcs return the blog in its new status,
+// a blog that isn't in the status the transition starts from gets a FAILED_PRECONDITION error
+type SubmitForReviewReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *SubmitForReviewReq) Reset() {
+	*x = SubmitForReviewReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitForReviewReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitForReviewReq) ProtoMessage() {}
+
+func (x *SubmitForReviewReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitForReviewReq.ProtoReflect.Descriptor instead.
+func (*SubmitForReviewReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SubmitForReviewReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type SubmitForReviewRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+}
+
+func (x *SubmitForReviewRes) Reset() {
+	*x = SubmitForReviewRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitForReviewRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitForReviewRes) ProtoMessage() {}
+
+func (x *SubmitForReviewRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitForReviewRes.ProtoReflect.Descriptor instead.
+func (*SubmitForReviewRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SubmitForReviewRes) GetBlog() *Blog {
+	if x != nil {
+		return x.Blog
+	}
+	return nil
+}
+
+// with a future publish_at the blog stays IN_REVIEW and the server publishes it at that time,
+// publishing again without publish_at publishes it right away
+type PublishBlogReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PublishAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"`
+}
+
+func (x *PublishBlogReq) Reset() {
+	*x = PublishBlogReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishBlogReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishBlogReq) ProtoMessage() {}
+
+func (x *PublishBlogReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishBlogReq.ProtoReflect.Descriptor instead.
+func (*PublishBlogReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PublishBlogReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PublishBlogReq) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
+type PublishBlogRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+}
+
+func (x *PublishBlogRes) Reset() {
+	*x = PublishBlogRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PublishBlogRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishBlogRes) ProtoMessage() {}
+
+func (x *PublishBlogRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishBlogRes.ProtoReflect.Descriptor instead.
+func (*PublishBlogRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PublishBlogRes) GetBlog() *Blog {
+	if x != nil {
+		return x.Blog
+	}
+	return nil
+}
+
+type ArchiveBlogReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ArchiveBlogReq) Reset() {
+	*x = ArchiveBlogReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveBlogReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveBlogReq) ProtoMessage() {}
+
+func (x *ArchiveBlogReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveBlogReq.ProtoReflect.Descriptor instead.
+func (*ArchiveBlogReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ArchiveBlogReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ArchiveBlogRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blog *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+}
+
+func (x *ArchiveBlogRes) Reset() {
+	*x = ArchiveBlogRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArchiveBlogRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveBlogRes) ProtoMessage() {}
+
+func (x *ArchiveBlogRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveBlogRes.ProtoReflect.Descriptor instead.
+func (*ArchiveBlogRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ArchiveBlogRes) GetBlog() *Blog {
+	if x != nil {
+		return x.Blog
+	}
+	return nil
+}
+
+// every create, update, restore and status change records an immutable revision of the blog
+type BlogRevision struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Revision  int64                  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`                   // 1 for the blog as created, incremented by every change
+	Blog      *Blog                  `protobuf:"bytes,2,opt,name=blog,proto3" json:"blog,omitempty"`                            // the blog as it was after this change
+	EditorId  string                 `protobuf:"bytes,3,opt,name=editor_id,json=editorId,proto3" json:"editor_id,omitempty"`    // who made the change
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // when the change was made
+}
+
+func (x *BlogRevision) Reset() {
+	*x = BlogRevision{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlogRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlogRevision) ProtoMessage() {}
+
+func (x *BlogRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlogRevision.ProtoReflect.Descriptor instead.
+func (*BlogRevision) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *BlogRevision) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *BlogRevision) GetBlog() *Blog {
+	if x != nil {
+		return x.Blog
+	}
+	return nil
+}
+
+func (x *BlogRevision) GetEditorId() string {
+	if x != nil {
+		return x.EditorId
+	}
+	return ""
+}
+
+func (x *BlogRevision) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// revisions are streamed oldest first
+type ListBlogRevisionsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *ListBlogRevisionsReq) Reset() {
+	*x = ListBlogRevisionsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBlogRevisionsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlogRevisionsReq) ProtoMessage() {}
+
+func (x *ListBlogRevisionsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlogRevisionsReq.ProtoReflect.Descriptor instead.
+func (*ListBlogRevisionsReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListBlogRevisionsReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListBlogRevisionsRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Revision *BlogRevision `protobuf:"bytes,1,opt,name=revision,proto3" json:"revision,omitempty"`
+}
+
+func (x *ListBlogRevisionsRes) Reset() {
+	*x = ListBlogRevisionsRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBlogRevisionsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlogRevisionsRes) ProtoMessage() {}
+
+func (x *ListBlogRevisionsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlogRevisionsRes.ProtoReflect.Descriptor instead.
+func (*ListBlogRevisionsRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListBlogRevisionsRes) GetRevision() *BlogRevision {
+	if x != nil {
+		return x.Revision
+	}
+	return nil
+}
+
+// restoring records a new revision with the content of an older one, history is never rewritten
+type RestoreBlogRevisionReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Revision        int64  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	EditorId        string `protobuf:"bytes,3,opt,name=editor_id,json=editorId,proto3" json:"editor_id,omitempty"`                       // defaults to the author of the restored revision
+	ExpectedVersion int64  `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"` // if set, the restore is rejected unless the blog is still at this version
+}
+
+func (x *RestoreBlogRevisionReq) Reset() {
+	*x = RestoreBlogRevisionReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreBlogRevisionReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBlogRevisionReq) ProtoMessage() {}
+
+func (x *RestoreBlogRevisionReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBlogRevisionReq.ProtoReflect.Descriptor instead.
+func (*RestoreBlogRevisionReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RestoreBlogRevisionReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RestoreBlogRevisionReq) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *RestoreBlogRevisionReq) GetEditorId() string {
+	if x != nil {
+		return x.EditorId
+	}
+	return ""
+}
+
+func (x *RestoreBlogRevisionReq) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RestoreBlogRevisionRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blog     *Blog `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Revision int64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // the new revision
+}
+
+func (x *RestoreBlogRevisionRes) Reset() {
+	*x = RestoreBlogRevisionRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreBlogRevisionRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBlogRevisionRes) ProtoMessage() {}
+
+func (x *RestoreBlogRevisionRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBlogRevisionRes.ProtoReflect.Descriptor instead.
+func (*RestoreBlogRevisionRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RestoreBlogRevisionRes) GetBlog() *Blog {
+	if x != nil {
+		return x.Blog
+	}
+	return nil
+}
+
+func (x *RestoreBlogRevisionRes) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// ListBlogs will use server-streaming
+type ListBlogReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AuthorId      string                    `protobuf:"bytes,1,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`          // only blogs of this author, blank for all authors
+	TitlePrefix   string                    `protobuf:"bytes,2,opt,name=title_prefix,json=titlePrefix,proto3" json:"title_prefix,omitempty"` // only blogs whose title starts with this prefix
+	SortBy        ListBlogReq_SortField     `protobuf:"varint,3,opt,name=sort_by,json=sortBy,proto3,enum=blog.ListBlogReq_SortField" json:"sort_by,omitempty"`
+	SortDirection ListBlogReq_SortDirection `protobuf:"varint,4,opt,name=sort_direction,json=sortDirection,proto3,enum=blog.ListBlogReq_SortDirection" json:"sort_direction,omitempty"`
+	PageSize      int32                     `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // max blogs sent on the stream, 0 means no limit
+	PageToken     string                    `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the last blog received, to resume a listing
+	Status        Blog_Status               `protobuf:"varint,7,opt,name=status,proto3,enum=blog.Blog_Status" json:"status,omitempty"` // only blogs in this status, UNSPECIFIED for all
+}
+
+func (x *ListBlogReq) Reset() {
+	*x = ListBlogReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBlogReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlogReq) ProtoMessage() {}
+
+func (x *ListBlogReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlogReq.ProtoReflect.Descriptor instead.
+func (*ListBlogReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListBlogReq) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *ListBlogReq) GetTitlePrefix() string {
+	if x != nil {
+		return x.TitlePrefix
+	}
+	return ""
+}
+
+func (x *ListBlogReq) GetSortBy() ListBlogReq_SortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return ListBlogReq_ID
+}
+
+func (x *ListBlogReq) GetSortDirection() ListBlogReq_SortDirection {
+	if x != nil {
+		return x.SortDirection
+	}
+	return ListBlogReq_ASC
+}
+
+func (x *ListBlogReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListBlogReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListBlogReq) GetStatus() Blog_Status {
+	if x != nil {
+		return x.Status
+	}
+	return Blog_UNSPECIFIED
+}
+
+type ListBlogRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blog          *Blog  `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // opaque cursor pointing right after this blog
+}
+
+func (x *ListBlogRes) Reset() {
+	*x = ListBlogRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListBlogRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBlogRes) ProtoMessage() {}
+
+func (x *ListBlogRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBlogRes.ProtoReflect.Descriptor instead.
+func (*ListBlogRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListBlogRes) GetBlog() *Blog {
+	if x != nil {
+		return x.Blog
+	}
+	return nil
+}
+
+func (x *ListBlogRes) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// full-text search in the titles and contents of the blogs outside the trash, best matches first.
+// Any of the words of the query can match, "quoted phrases" must all match.
+type SearchBlogsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Query    string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	AuthorId string `protobuf:"bytes,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"` // only blogs of this author, blank for all authors
+	Limit    int32  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                      // max results, 0 means 20, at most 100
+}
+
+func (x *SearchBlogsReq) Reset() {
+	*x = SearchBlogsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchBlogsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchBlogsReq) ProtoMessage() {}
+
+func (x *SearchBlogsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchBlogsReq.ProtoReflect.Descriptor instead.
+func (*SearchBlogsReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SearchBlogsReq) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchBlogsReq) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *SearchBlogsReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchBlogsRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Blog           *Blog   `protobuf:"bytes,1,opt,name=blog,proto3" json:"blog,omitempty"`
+	Score          float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`                                       // relevance, only comparable between results of the same search
+	TitleSnippet   string  `protobuf:"bytes,3,opt,name=title_snippet,json=titleSnippet,proto3" json:"title_snippet,omitempty"`       // the title with the matches surrounded by <mark></mark>
+	ContentSnippet string  `protobuf:"bytes,4,opt,name=content_snippet,json=contentSnippet,proto3" json:"content_snippet,omitempty"` // the part of the content around the first match, marked the same way
+}
+
+func (x *SearchBlogsRes) Reset() {
+	*x = SearchBlogsRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchBlogsRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchBlogsRes) ProtoMessage() {}
+
+func (x *SearchBlogsRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchBlogsRes.ProtoReflect.Descriptor instead.
+func (*SearchBlogsRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SearchBlogsRes) GetBlog() *Blog {
+	if x != nil {
+		return x.Blog
+	}
+	return nil
+}
+
+func (x *SearchBlogsRes) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SearchBlogsRes) GetTitleSnippet() string {
+	if x != nil {
+		return x.TitleSnippet
+	}
+	return ""
+}
+
+func (x *SearchBlogsRes) GetContentSnippet() string {
+	if x != nil {
+		return x.ContentSnippet
+	}
+	return ""
+}
+
+type ApiKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                      // what the key is for, for humans
+	Scopes    []string               `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`                  // blogs:read and/or blogs:write
+	OwnerId   string                 `protobuf:"bytes,4,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"` // set by the server, the user the key acts for
+	Prefix    string                 `protobuf:"bytes,5,opt,name=prefix,proto3" json:"prefix,omitempty"`                  // set by the server, the start of the secret to tell keys apart
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	RotatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=rotated_at,json=rotatedAt,proto3" json:"rotated_at,omitempty"` // set once the key has been rotated
+	RevokedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"` // set once the key has been revoked
+}
+
+func (x *ApiKey) Reset() {
+	*x = ApiKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApiKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApiKey) ProtoMessage() {}
+
+func (x *ApiKey) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApiKey.ProtoReflect.Descriptor instead.
+func (*ApiKey) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ApiKey) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ApiKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ApiKey) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ApiKey) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *ApiKey) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ApiKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ApiKey) GetRotatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RotatedAt
+	}
+	return nil
+}
+
+func (x *ApiKey) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+type CreateApiKeyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Scopes []string `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"` // at least one
+}
+
+func (x *CreateApiKeyReq) Reset() {
+	*x = CreateApiKeyReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateApiKeyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApiKeyReq) ProtoMessage() {}
+
+func (x *CreateApiKeyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApiKeyReq.ProtoReflect.Descriptor instead.
+func (*CreateApiKeyReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CreateApiKeyReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateApiKeyReq) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+type CreateApiKeyRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiKey *ApiKey `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Secret string  `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"` // the value of the x-api-key metadata, only the server's hash of it is stored
+}
+
+func (x *CreateApiKeyRes) Reset() {
+	*x = CreateApiKeyRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateApiKeyRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApiKeyRes) ProtoMessage() {}
+
+func (x *CreateApiKeyRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApiKeyRes.ProtoReflect.Descriptor instead.
+func (*CreateApiKeyRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CreateApiKeyRes) GetApiKey() *ApiKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateApiKeyRes) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type ListApiKeysReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListApiKeysReq) Reset() {
+	*x = ListApiKeysReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListApiKeysReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysReq) ProtoMessage() {}
+
+func (x *ListApiKeysReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysReq.ProtoReflect.Descriptor instead.
+func (*ListApiKeysReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{34}
+}
+
+type ListApiKeysRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiKey *ApiKey `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+}
+
+func (x *ListApiKeysRes) Reset() {
+	*x = ListApiKeysRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListApiKeysRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysRes) ProtoMessage() {}
+
+func (x *ListApiKeysRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysRes.ProtoReflect.Descriptor instead.
+func (*ListApiKeysRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListApiKeysRes) GetApiKey() *ApiKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+type RevokeApiKeyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *RevokeApiKeyReq) Reset() {
+	*x = RevokeApiKeyReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokeApiKeyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeApiKeyReq) ProtoMessage() {}
+
+func (x *RevokeApiKeyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeApiKeyReq.ProtoReflect.Descriptor instead.
+func (*RevokeApiKeyReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RevokeApiKeyReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeApiKeyRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiKey *ApiKey `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+}
+
+func (x *RevokeApiKeyRes) Reset() {
+	*x = RevokeApiKeyRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokeApiKeyRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeApiKeyRes) ProtoMessage() {}
+
+func (x *RevokeApiKeyRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeApiKeyRes.ProtoReflect.Descriptor instead.
+func (*RevokeApiKeyRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RevokeApiKeyRes) GetApiKey() *ApiKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+type RotateApiKeyReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *RotateApiKeyReq) Reset() {
+	*x = RotateApiKeyReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateApiKeyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateApiKeyReq) ProtoMessage() {}
+
+func (x *RotateApiKeyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateApiKeyReq.ProtoReflect.Descriptor instead.
+func (*RotateApiKeyReq) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RotateApiKeyReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RotateApiKeyRes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ApiKey *ApiKey `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Secret string  `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"` // the new secret
+}
+
+func (x *RotateApiKeyRes) Reset() {
+	*x = RotateApiKeyRes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_blog_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateApiKeyRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateApiKeyRes) ProtoMessage() {}
+
+func (x *RotateApiKeyRes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_blog_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateApiKeyRes.ProtoReflect.Descriptor instead.
+func (*RotateApiKeyRes) Descriptor() ([]byte, []int) {
+	return file_proto_blog_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RotateApiKeyRes) GetApiKey() *ApiKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *RotateApiKeyRes) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+var File_proto_blog_proto protoreflect.FileDescriptor
+
+var file_proto_blog_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6d, 0x61,
+	0x73, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb5, 0x02, 0x0a, 0x04, 0x42, 0x6c,
+	0x6f, 0x67, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x67,
+	0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x5f,
+	0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x74, 0x22,
+	0x50, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x52,
+	0x41, 0x46, 0x54, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x5f, 0x52, 0x45, 0x56, 0x49,
+	0x45, 0x57, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x53, 0x48, 0x45,
+	0x44, 0x10, 0x03, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x52, 0x43, 0x48, 0x49, 0x56, 0x45, 0x44, 0x10,
+	0x04, 0x22, 0x75, 0x0a, 0x0f, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4d, 0x69, 0x73, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f,
+	0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x27, 0x0a, 0x0f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x2f, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f,
+	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42,
+	0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22, 0x2f, 0x0a, 0x0d, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c,
+	0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e,
+	0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22, 0x6a, 0x0a, 0x0b, 0x52, 0x65,
+	0x61, 0x64, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x05, 0x61, 0x73, 0x5f, 0x6f, 0x66, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x04, 0x61, 0x73, 0x4f, 0x66, 0x22, 0x49, 0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6c,
+	0x6f, 0x67, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52,
+	0x04, 0x62, 0x6c, 0x6f, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0xb4, 0x01, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67,
+	0x52, 0x65, 0x71, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62,
+	0x6c, 0x6f, 0x67, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x64, 0x69, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x65, 0x64, 0x69, 0x74, 0x6f, 0x72, 0x49, 0x64,
+	0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65,
+	0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3b, 0x0a, 0x0b, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x22, 0x4b, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f,
 	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42,
-	0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x32, 0xa3, 0x02, 0x0a, 0x0b, 0x42, 0x6c,
-	0x6f, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x38, 0x0a, 0x0a, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x12, 0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x62,
-	0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65,
-	0x73, 0x22, 0x00, 0x12, 0x32, 0x0a, 0x08, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6c, 0x6f, 0x67, 0x12,
-	0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6c, 0x6f, 0x67, 0x52,
-	0x65, 0x71, 0x1a, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6c,
-	0x6f, 0x67, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12, 0x38, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x42, 0x6c, 0x6f, 0x67, 0x12, 0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x62, 0x6c, 0x6f,
-	0x67, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x22,
-	0x00, 0x12, 0x38, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x12,
-	0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f,
-	0x67, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x44, 0x65, 0x6c, 0x65,
-	0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x22, 0x00, 0x12, 0x32, 0x0a, 0x08, 0x4c,
-	0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x12, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x11, 0x2e, 0x62, 0x6c, 0x6f,
-	0x67, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x22, 0x00, 0x42,
-	0x08, 0x5a, 0x06, 0x62, 0x6c, 0x6f, 0x67, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x4a, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42,
+	0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0x29, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52,
+	0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x21, 0x0a, 0x0f,
+	0x55, 0x6e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x31, 0x0a, 0x0f, 0x55, 0x6e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52,
+	0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c,
+	0x6f, 0x67, 0x22, 0x1e, 0x0a, 0x0c, 0x50, 0x75, 0x72, 0x67, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52,
+	0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x28, 0x0a, 0x0c, 0x50, 0x75, 0x72, 0x67, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52,
+	0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x32, 0x0a, 0x13,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x67, 0x73,
+	0x52, 0x65, 0x71, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x64,
+	0x22, 0x70, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x42,
+	0x6c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f,
+	0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x12, 0x39, 0x0a, 0x0a, 0x64, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64,
+	0x41, 0x74, 0x22, 0x24, 0x0a, 0x12, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x46, 0x6f, 0x72, 0x52,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x34, 0x0a, 0x12, 0x53, 0x75, 0x62, 0x6d,
+	0x69, 0x74, 0x46, 0x6f, 0x72, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x73, 0x12, 0x1e,
+	0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62,
+	0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22, 0x5b,
+	0x0a, 0x0e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x39, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x5f, 0x61, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x74, 0x22, 0x30, 0x0a, 0x0e, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a,
+	0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x6c,
+	0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x22, 0x20, 0x0a,
+	0x0e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x30, 0x0a, 0x0e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65,
+	0x73, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f,
+	0x67, 0x22, 0xa2, 0x01, 0x0a, 0x0c, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1e,
+	0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62,
+	0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x12, 0x1b,
+	0x0a, 0x09, 0x65, 0x64, 0x69, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x65, 0x64, 0x69, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x39, 0x0a, 0x0a, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x26, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c,
+	0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x46,
+	0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e,
+	0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x72, 0x65,
+	0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x8c, 0x01, 0x0a, 0x16, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a,
+	0x09, 0x65, 0x64, 0x69, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x65, 0x64, 0x69, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x78,
+	0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x54, 0x0a, 0x16, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x12,
+	0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x85, 0x03, 0x0a, 0x0b,
+	0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x12, 0x1b, 0x0a, 0x09, 0x61,
+	0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x74, 0x69, 0x74, 0x6c, 0x65, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x34, 0x0a, 0x07, 0x73,
+	0x6f, 0x72, 0x74, 0x5f, 0x62, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x62,
+	0x6c, 0x6f, 0x67, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x2e,
+	0x53, 0x6f, 0x72, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x06, 0x73, 0x6f, 0x72, 0x74, 0x42,
+	0x79, 0x12, 0x46, 0x0a, 0x0e, 0x73, 0x6f, 0x72, 0x74, 0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x62, 0x6c, 0x6f, 0x67,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x2e, 0x53, 0x6f, 0x72,
+	0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x73, 0x6f, 0x72, 0x74,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67,
+	0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61,
+	0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f,
+	0x67, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x22, 0x2d, 0x0a, 0x09, 0x53, 0x6f, 0x72, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x06, 0x0a,
+	0x02, 0x49, 0x44, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x49, 0x54, 0x4c, 0x45, 0x10, 0x01,
+	0x12, 0x0d, 0x0a, 0x09, 0x41, 0x55, 0x54, 0x48, 0x4f, 0x52, 0x5f, 0x49, 0x44, 0x10, 0x02, 0x22,
+	0x22, 0x0a, 0x0d, 0x53, 0x6f, 0x72, 0x74, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x07, 0x0a, 0x03, 0x41, 0x53, 0x43, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x45, 0x53,
+	0x43, 0x10, 0x01, 0x22, 0x55, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52,
+	0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c,
+	0x6f, 0x67, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78,
+	0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x59, 0x0a, 0x0e, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65,
+	0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x49, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x94, 0x01, 0x0a, 0x0e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x42, 0x6c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x67,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x42, 0x6c,
+	0x6f, 0x67, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x23,
+	0x0a, 0x0d, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x5f, 0x73, 0x6e, 0x69, 0x70, 0x70, 0x65, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x53, 0x6e, 0x69, 0x70,
+	0x70, 0x65, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x73,
+	0x6e, 0x69, 0x70, 0x70, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x53, 0x6e, 0x69, 0x70, 0x70, 0x65, 0x74, 0x22, 0xa8, 0x02, 0x0a,
+	0x06, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x6f,
+	0x70, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x12, 0x39, 0x0a, 0x0a, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a,
+	0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x72, 0x65,
+	0x76, 0x6f, 0x6b, 0x65, 0x64, 0x41, 0x74, 0x22, 0x3d, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x22, 0x50, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x07, 0x61, 0x70, 0x69,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x62, 0x6c, 0x6f,
+	0x67, 0x2e, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x06, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0x10, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74,
+	0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65, 0x71, 0x22, 0x37, 0x0a, 0x0e, 0x4c, 0x69,
+	0x73, 0x74, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x07,
+	0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x06, 0x61, 0x70, 0x69,
+	0x4b, 0x65, 0x79, 0x22, 0x21, 0x0a, 0x0f, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x41, 0x70, 0x69,
+	0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x38, 0x0a, 0x0f, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
+	0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x07, 0x61, 0x70, 0x69,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x62, 0x6c, 0x6f,
+	0x67, 0x2e, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x06, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79,
+	0x22, 0x21, 0x0a, 0x0f, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79,
+	0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x22, 0x50, 0x0a, 0x0f, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x41, 0x70, 0x69,
+	0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x07, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x41,
+	0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x06, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x32, 0xaa, 0x0a, 0x0a, 0x0b, 0x42, 0x6c, 0x6f, 0x67, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x4f, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42,
+	0x6c, 0x6f, 0x67, 0x12, 0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x22, 0x17, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x11, 0x22, 0x09, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73,
+	0x3a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x12, 0x48, 0x0a, 0x08, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6c,
+	0x6f, 0x67, 0x12, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x42, 0x6c,
+	0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x52, 0x65, 0x61,
+	0x64, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x22, 0x16, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10,
+	0x12, 0x0e, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d,
+	0x12, 0x59, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x12, 0x13,
+	0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67,
+	0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b,
+	0x32, 0x13, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73, 0x2f, 0x7b, 0x62, 0x6c, 0x6f,
+	0x67, 0x2e, 0x69, 0x64, 0x7d, 0x3a, 0x04, 0x62, 0x6c, 0x6f, 0x67, 0x12, 0x4e, 0x0a, 0x0a, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x12, 0x13, 0x2e, 0x62, 0x6c, 0x6f, 0x67,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x13,
+	0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67,
+	0x52, 0x65, 0x73, 0x22, 0x16, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x2a, 0x0e, 0x2f, 0x76, 0x31,
+	0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12, 0x89, 0x01, 0x0a, 0x13,
+	0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x1a, 0x1c, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65,
+	0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x22,
+	0x36, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x30, 0x22, 0x2b, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f,
+	0x67, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x2f, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x2f, 0x7b, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x7d, 0x3a, 0x72, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x60, 0x0a, 0x0c, 0x55, 0x6e, 0x64, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x12, 0x15, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x55,
+	0x6e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x15,
+	0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x55, 0x6e, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x42, 0x6c,
+	0x6f, 0x67, 0x52, 0x65, 0x73, 0x22, 0x22, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1c, 0x22, 0x17, 0x2f,
+	0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x73, 0x68, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x3a, 0x75, 0x6e,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x4b, 0x0a, 0x09, 0x50, 0x75, 0x72,
+	0x67, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x12, 0x12, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x50, 0x75,
+	0x72, 0x67, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x12, 0x2e, 0x62, 0x6c, 0x6f,
+	0x67, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x22, 0x16,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x2a, 0x0e, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x73,
+	0x68, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x12, 0x70, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x46, 0x6f, 0x72, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x67,
+	0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x46, 0x6f, 0x72, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x52, 0x65, 0x71, 0x1a, 0x18, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x46, 0x6f, 0x72, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x73, 0x22, 0x29, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x23, 0x22, 0x1e, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73,
+	0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x3a, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x46, 0x6f, 0x72, 0x52,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x3a, 0x01, 0x2a, 0x12, 0x5c, 0x0a, 0x0b, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x73, 0x68, 0x42, 0x6c, 0x6f, 0x67, 0x12, 0x14, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x50,
+	0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e,
+	0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x42, 0x6c, 0x6f, 0x67,
+	0x52, 0x65, 0x73, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x22, 0x16, 0x2f, 0x76, 0x31,
+	0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x3a, 0x70, 0x75, 0x62, 0x6c,
+	0x69, 0x73, 0x68, 0x3a, 0x01, 0x2a, 0x12, 0x5c, 0x0a, 0x0b, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76,
+	0x65, 0x42, 0x6c, 0x6f, 0x67, 0x12, 0x14, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x41, 0x72, 0x63,
+	0x68, 0x69, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e, 0x62, 0x6c,
+	0x6f, 0x67, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65,
+	0x73, 0x22, 0x21, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1b, 0x3a, 0x01, 0x2a, 0x22, 0x16, 0x2f, 0x76,
+	0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d, 0x3a, 0x61, 0x72, 0x63,
+	0x68, 0x69, 0x76, 0x65, 0x12, 0x45, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67,
+	0x12, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67,
+	0x52, 0x65, 0x71, 0x1a, 0x11, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42,
+	0x6c, 0x6f, 0x67, 0x52, 0x65, 0x73, 0x22, 0x11, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0b, 0x12, 0x09,
+	0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73, 0x30, 0x01, 0x12, 0x6f, 0x0a, 0x11, 0x4c,
+	0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x1a, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67,
+	0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x62,
+	0x6c, 0x6f, 0x67, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x67, 0x52, 0x65, 0x76, 0x69,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x22, 0x20, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1a,
+	0x12, 0x18, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73, 0x2f, 0x7b, 0x69, 0x64, 0x7d,
+	0x2f, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x30, 0x01, 0x12, 0x5d, 0x0a, 0x10,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x67, 0x73,
+	0x12, 0x19, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x19, 0x2e, 0x62, 0x6c,
+	0x6f, 0x67, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x42, 0x6c,
+	0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x22, 0x11, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0b, 0x12, 0x09,
+	0x2f, 0x76, 0x31, 0x2f, 0x74, 0x72, 0x61, 0x73, 0x68, 0x30, 0x01, 0x12, 0x55, 0x0a, 0x0b, 0x53,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x67, 0x73, 0x12, 0x14, 0x2e, 0x62, 0x6c, 0x6f,
+	0x67, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x6c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71,
+	0x1a, 0x14, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x6c,
+	0x6f, 0x67, 0x73, 0x52, 0x65, 0x73, 0x22, 0x18, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x12, 0x10,
+	0x2f, 0x76, 0x31, 0x2f, 0x62, 0x6c, 0x6f, 0x67, 0x73, 0x3a, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x30, 0x01, 0x32, 0xf5, 0x02, 0x0a, 0x0d, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x54, 0x0a, 0x0c, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x70,
+	0x69, 0x4b, 0x65, 0x79, 0x12, 0x15, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x62, 0x6c,
+	0x6f, 0x67, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52,
+	0x65, 0x73, 0x22, 0x16, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x22, 0x0b, 0x2f, 0x76, 0x31, 0x2f,
+	0x61, 0x70, 0x69, 0x6b, 0x65, 0x79, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x50, 0x0a, 0x0b, 0x4c, 0x69,
+	0x73, 0x74, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x14, 0x2e, 0x62, 0x6c, 0x6f, 0x67,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65, 0x71, 0x1a,
+	0x14, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x70, 0x69, 0x4b, 0x65,
+	0x79, 0x73, 0x52, 0x65, 0x73, 0x22, 0x13, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0d, 0x12, 0x0b, 0x2f,
+	0x76, 0x31, 0x2f, 0x61, 0x70, 0x69, 0x6b, 0x65, 0x79, 0x73, 0x30, 0x01, 0x12, 0x5d, 0x0a, 0x0c,
+	0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x12, 0x15, 0x2e, 0x62,
+	0x6c, 0x6f, 0x67, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79,
+	0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b,
+	0x65, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x19, 0x22, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x70, 0x69, 0x6b, 0x65, 0x79, 0x73, 0x2f,
+	0x7b, 0x69, 0x64, 0x7d, 0x3a, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x12, 0x5d, 0x0a, 0x0c, 0x52,
+	0x6f, 0x74, 0x61, 0x74, 0x65, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x12, 0x15, 0x2e, 0x62, 0x6c,
+	0x6f, 0x67, 0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52,
+	0x65, 0x71, 0x1a, 0x15, 0x2e, 0x62, 0x6c, 0x6f, 0x67, 0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65,
+	0x41, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x19, 0x22, 0x17, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x70, 0x69, 0x6b, 0x65, 0x79, 0x73, 0x2f, 0x7b,
+	0x69, 0x64, 0x7d, 0x3a, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x42, 0x2d, 0x5a, 0x2b, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x76, 0x61, 0x69, 0x62, 0x68, 0x61, 0x76,
+	0x2f, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x31, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x3b, 0x62, 0x6c, 0x6f, 0x67, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x33,
 }
 
@@ -636,42 +2815,129 @@ func file_proto_blog_proto_rawDescGZIP() []byte {
 	return file_proto_blog_proto_rawDescData
 }
 
-var file_proto_blog_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_proto_blog_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_proto_blog_proto_msgTypes = make([]protoimpl.MessageInfo, 40)
 var file_proto_blog_proto_goTypes = []interface{}{
-	(*Blog)(nil),          // 0: blog.Blog
-	(*CreateBlogReq)(nil), // 1: blog.CreateBlogReq
-	(*CreateBlogRes)(nil), // 2: blog.CreateBlogRes
-	(*ReadBlogReq)(nil),   // 3: blog.ReadBlogReq
-	(*ReadBlogRes)(nil),   // 4: blog.ReadBlogRes
-	(*UpdateBlogReq)(nil), // 5: blog.UpdateBlogReq
-	(*UpdateBlogRes)(nil), // 6: blog.UpdateBlogRes
-	(*DeleteBlogReq)(nil), // 7: blog.DeleteBlogReq
-	(*DeleteBlogRes)(nil), // 8: blog.DeleteBlogRes
-	(*ListBlogReq)(nil),   // 9: blog.ListBlogReq
-	(*ListBlogRes)(nil),   // 10: blog.ListBlogRes
+	(Blog_Status)(0),               // 0: blog.Blog.Status
+	(ListBlogReq_SortField)(0),     // 1: blog.ListBlogReq.SortField
+	(ListBlogReq_SortDirection)(0), // 2: blog.ListBlogReq.SortDirection
+	(*Blog)(nil),                   // 3: blog.Blog
+	(*VersionMismatch)(nil),        // 4: blog.VersionMismatch
+	(*CreateBlogReq)(nil),          // 5: blog.CreateBlogReq
+	(*CreateBlogRes)(nil),          // 6: blog.CreateBlogRes
+	(*ReadBlogReq)(nil),            // 7: blog.ReadBlogReq
+	(*ReadBlogRes)(nil),            // 8: blog.ReadBlogRes
+	(*UpdateBlogReq)(nil),          // 9: blog.UpdateBlogReq
+	(*UpdateBlogRes)(nil),          // 10: blog.UpdateBlogRes
+	(*DeleteBlogReq)(nil),          // 11: blog.DeleteBlogReq
+	(*DeleteBlogRes)(nil),          // 12: blog.DeleteBlogRes
+	(*UndeleteBlogReq)(nil),        // 13: blog.UndeleteBlogReq
+	(*UndeleteBlogRes)(nil),        // 14: blog.UndeleteBlogRes
+	(*PurgeBlogReq)(nil),           // 15: blog.PurgeBlogReq
+	(*PurgeBlogRes)(nil),           // 16: blog.PurgeBlogRes
+	(*ListDeletedBlogsReq)(nil),    // 17: blog.ListDeletedBlogsReq
+	(*ListDeletedBlogsRes)(nil),    // 18: blog.ListDeletedBlogsRes
+	(*SubmitForReviewReq)(nil),     // 19: blog.SubmitForReviewReq
+	(*SubmitForReviewRes)(nil),     // 20: blog.SubmitForReviewRes
+	(*PublishBlogReq)(nil),         // 21: blog.PublishBlogReq
+	(*PublishBlogRes)(nil),         // 22: blog.PublishBlogRes
+	(*ArchiveBlogReq)(nil),         // 23: blog.ArchiveBlogReq
+	(*ArchiveBlogRes)(nil),         // 24: blog.ArchiveBlogRes
+	(*BlogRevision)(nil),           // 25: blog.BlogRevision
+	(*ListBlogRevisionsReq)(nil),   // 26: blog.ListBlogRevisionsReq
+	(*ListBlogRevisionsRes)(nil),   // 27: blog.ListBlogRevisionsRes
+	(*RestoreBlogRevisionReq)(nil), // 28: blog.RestoreBlogRevisionReq
+	(*RestoreBlogRevisionRes)(nil), // 29: blog.RestoreBlogRevisionRes
+	(*ListBlogReq)(nil),            // 30: blog.ListBlogReq
+	(*ListBlogRes)(nil),            // 31: blog.ListBlogRes
+	(*SearchBlogsReq)(nil),         // 32: blog.SearchBlogsReq
+	(*SearchBlogsRes)(nil),         // 33: blog.SearchBlogsRes
+	(*ApiKey)(nil),                 // 34: blog.ApiKey
+	(*CreateApiKeyReq)(nil),        // 35: blog.CreateApiKeyReq
+	(*CreateApiKeyRes)(nil),        // 36: blog.CreateApiKeyRes
+	(*ListApiKeysReq)(nil),         // 37: blog.ListApiKeysReq
+	(*ListApiKeysRes)(nil),         // 38: blog.ListApiKeysRes
+	(*RevokeApiKeyReq)(nil),        // 39: blog.RevokeApiKeyReq
+	(*RevokeApiKeyRes)(nil),        // 40: blog.RevokeApiKeyRes
+	(*RotateApiKeyReq)(nil),        // 41: blog.RotateApiKeyReq
+	(*RotateApiKeyRes)(nil),        // 42: blog.RotateApiKeyRes
+	(*timestamppb.Timestamp)(nil),  // 43: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),  // 44: google.protobuf.FieldMask
 }
 var file_proto_blog_proto_depIdxs = []int32{
-	0,  // 0: blog.CreateBlogReq.blog:type_name -> blog.Blog
-	0,  // 1: blog.CreateBlogRes.blog:type_name -> blog.Blog
-	0,  // 2: blog.ReadBlogRes.blog:type_name -> blog.Blog
-	0,  // 3: blog.UpdateBlogReq.blog:type_name -> blog.Blog
-	0,  // 4: blog.UpdateBlogRes.blog:type_name -> blog.Blog
-	0,  // 5: blog.ListBlogRes.blog:type_name -> blog.Blog
-	1,  // 6: blog.BlogService.CreateBlog:input_type -> blog.CreateBlogReq
-	3,  // 7: blog.BlogService.ReadBlog:input_type -> blog.ReadBlogReq
-	5,  // 8: blog.BlogService.UpdateBlog:input_type -> blog.UpdateBlogReq
-	7,  // 9: blog.BlogService.DeleteBlog:input_type -> blog.DeleteBlogReq
-	9,  // 10: blog.BlogService.ListBlog:input_type -> blog.ListBlogReq
-	2,  // 11: blog.BlogService.CreateBlog:output_type -> blog.CreateBlogRes
-	4,  // 12: blog.BlogService.ReadBlog:output_type -> blog.ReadBlogRes
-	6,  // 13: blog.BlogService.UpdateBlog:output_type -> blog.UpdateBlogRes
-	8,  // 14: blog.BlogService.DeleteBlog:output_type -> blog.DeleteBlogRes
-	9,  // 15: blog.BlogService.ListBlog:output_type -> blog.ListBlogReq
-	11, // [11:16] is the sub-list for method output_type
-	6,  // [6:11] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+	0,  // 0: blog.Blog.status:type_name -> blog.Blog.Status
+	43, // 1: blog.Blog.publish_at:type_name -> google.protobuf.Timestamp
+	3,  // 2: blog.CreateBlogReq.blog:type_name -> blog.Blog
+	3,  // 3: blog.CreateBlogRes.blog:type_name -> blog.Blog
+	43, // 4: blog.ReadBlogReq.as_of:type_name -> google.protobuf.Timestamp
+	3,  // 5: blog.ReadBlogRes.blog:type_name -> blog.Blog
+	3,  // 6: blog.UpdateBlogReq.blog:type_name -> blog.Blog
+	44, // 7: blog.UpdateBlogReq.update_mask:type_name -> google.protobuf.FieldMask
+	3,  // 8: blog.UpdateBlogRes.blog:type_name -> blog.Blog
+	3,  // 9: blog.UndeleteBlogRes.blog:type_name -> blog.Blog
+	3,  // 10: blog.ListDeletedBlogsRes.blog:type_name -> blog.Blog
+	43, // 11: blog.ListDeletedBlogsRes.deleted_at:type_name -> google.protobuf.Timestamp
+	3,  // 12: blog.SubmitForReviewRes.blog:type_name -> blog.Blog
+	43, // 13: blog.PublishBlogReq.publish_at:type_name -> google.protobuf.Timestamp
+	3,  // 14: blog.PublishBlogRes.blog:type_name -> blog.Blog
+	3,  // 15: blog.ArchiveBlogRes.blog:type_name -> blog.Blog
+	3,  // 16: blog.BlogRevision.blog:type_name -> blog.Blog
+	43, // 17: blog.BlogRevision.created_at:type_name -> google.protobuf.Timestamp
+	25, // 18: blog.ListBlogRevisionsRes.revision:type_name -> blog.BlogRevision
+	3,  // 19: blog.RestoreBlogRevisionRes.blog:type_name -> blog.Blog
+	1,  // 20: blog.ListBlogReq.sort_by:type_name -> blog.ListBlogReq.SortField
+	2,  // 21: blog.ListBlogReq.sort_direction:type_name -> blog.ListBlogReq.SortDirection
+	0,  // 22: blog.ListBlogReq.status:type_name -> blog.Blog.Status
+	3,  // 23: blog.ListBlogRes.blog:type_name -> blog.Blog
+	3,  // 24: blog.SearchBlogsRes.blog:type_name -> blog.Blog
+	43, // 25: blog.ApiKey.created_at:type_name -> google.protobuf.Timestamp
+	43, // 26: blog.ApiKey.rotated_at:type_name -> google.protobuf.Timestamp
+	43, // 27: blog.ApiKey.revoked_at:type_name -> google.protobuf.Timestamp
+	34, // 28: blog.CreateApiKeyRes.api_key:type_name -> blog.ApiKey
+	34, // 29: blog.ListApiKeysRes.api_key:type_name -> blog.ApiKey
+	34, // 30: blog.RevokeApiKeyRes.api_key:type_name -> blog.ApiKey
+	34, // 31: blog.RotateApiKeyRes.api_key:type_name -> blog.ApiKey
+	5,  // 32: blog.BlogService.CreateBlog:input_type -> blog.CreateBlogReq
+	7,  // 33: blog.BlogService.ReadBlog:input_type -> blog.ReadBlogReq
+	9,  // 34: blog.BlogService.UpdateBlog:input_type -> blog.UpdateBlogReq
+	11, // 35: blog.BlogService.DeleteBlog:input_type -> blog.DeleteBlogReq
+	28, // 36: blog.BlogService.RestoreBlogRevision:input_type -> blog.RestoreBlogRevisionReq
+	13, // 37: blog.BlogService.UndeleteBlog:input_type -> blog.UndeleteBlogReq
+	15, // 38: blog.BlogService.PurgeBlog:input_type -> blog.PurgeBlogReq
+	19, // 39: blog.BlogService.SubmitForReview:input_type -> blog.SubmitForReviewReq
+	21, // 40: blog.BlogService.PublishBlog:input_type -> blog.PublishBlogReq
+	23, // 41: blog.BlogService.ArchiveBlog:input_type -> blog.ArchiveBlogReq
+	30, // 42: blog.BlogService.ListBlog:input_type -> blog.ListBlogReq
+	26, // 43: blog.BlogService.ListBlogRevisions:input_type -> blog.ListBlogRevisionsReq
+	17, // 44: blog.BlogService.ListDeletedBlogs:input_type -> blog.ListDeletedBlogsReq
+	32, // 45: blog.BlogService.SearchBlogs:input_type -> blog.SearchBlogsReq
+	35, // 46: blog.ApiKeyService.CreateApiKey:input_type -> blog.CreateApiKeyReq
+	37, // 47: blog.ApiKeyService.ListApiKeys:input_type -> blog.ListApiKeysReq
+	39, // 48: blog.ApiKeyService.RevokeApiKey:input_type -> blog.RevokeApiKeyReq
+	41, // 49: blog.ApiKeyService.RotateApiKey:input_type -> blog.RotateApiKeyReq
+	6,  // 50: blog.BlogService.CreateBlog:output_type -> blog.CreateBlogRes
+	8,  // 51: blog.BlogService.ReadBlog:output_type -> blog.ReadBlogRes
+	10, // 52: blog.BlogService.UpdateBlog:output_type -> blog.UpdateBlogRes
+	12, // 53: blog.BlogService.DeleteBlog:output_type -> blog.DeleteBlogRes
+	29, // 54: blog.BlogService.RestoreBlogRevision:output_type -> blog.RestoreBlogRevisionRes
+	14, // 55: blog.BlogService.UndeleteBlog:output_type -> blog.UndeleteBlogRes
+	16, // 56: blog.BlogService.PurgeBlog:output_type -> blog.PurgeBlogRes
+	20, // 57: blog.BlogService.SubmitForReview:output_type -> blog.SubmitForReviewRes
+	22, // 58: blog.BlogService.PublishBlog:output_type -> blog.PublishBlogRes
+	24, // 59: blog.BlogService.ArchiveBlog:output_type -> blog.ArchiveBlogRes
+	31, // 60: blog.BlogService.ListBlog:output_type -> blog.ListBlogRes
+	27, // 61: blog.BlogService.ListBlogRevisions:output_type -> blog.ListBlogRevisionsRes
+	18, // 62: blog.BlogService.ListDeletedBlogs:output_type -> blog.ListDeletedBlogsRes
+	33, // 63: blog.BlogService.SearchBlogs:output_type -> blog.SearchBlogsRes
+	36, // 64: blog.ApiKeyService.CreateApiKey:output_type -> blog.CreateApiKeyRes
+	38, // 65: blog.ApiKeyService.ListApiKeys:output_type -> blog.ListApiKeysRes
+	40, // 66: blog.ApiKeyService.RevokeApiKey:output_type -> blog.RevokeApiKeyRes
+	42, // 67: blog.ApiKeyService.RotateApiKey:output_type -> blog.RotateApiKeyRes
+	50, // [50:68] is the sub-list for method output_type
+	32, // [32:50] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_proto_blog_proto_init() }
@@ -692,8 +2958,188 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateBlogReq); i {
+		file_proto_blog_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VersionMismatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateBlogReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateBlogRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadBlogReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadBlogRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateBlogReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateBlogRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteBlogReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteBlogRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UndeleteBlogReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UndeleteBlogRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PurgeBlogReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PurgeBlogRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeletedBlogsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeletedBlogsRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitForReviewReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -704,8 +3150,8 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateBlogRes); i {
+		file_proto_blog_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitForReviewRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -716,8 +3162,8 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadBlogReq); i {
+		file_proto_blog_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishBlogReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -728,8 +3174,8 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadBlogRes); i {
+		file_proto_blog_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishBlogRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -740,8 +3186,8 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateBlogReq); i {
+		file_proto_blog_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveBlogReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -752,8 +3198,8 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateBlogRes); i {
+		file_proto_blog_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArchiveBlogRes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -764,8 +3210,8 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteBlogReq); i {
+		file_proto_blog_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlogRevision); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -776,8 +3222,8 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteBlogRes); i {
+		file_proto_blog_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBlogRevisionsReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -788,7 +3234,43 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_proto_blog_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBlogRevisionsRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreBlogRevisionReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreBlogRevisionRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListBlogReq); i {
 			case 0:
 				return &v.state
@@ -800,7 +3282,7 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
-		file_proto_blog_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_proto_blog_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListBlogRes); i {
 			case 0:
 				return &v.state
@@ -812,19 +3294,152 @@ func file_proto_blog_proto_init() {
 				return nil
 			}
 		}
+		file_proto_blog_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchBlogsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchBlogsRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApiKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateApiKeyReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateApiKeyRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListApiKeysReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListApiKeysRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeApiKeyReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeApiKeyRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateApiKeyReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_blog_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateApiKeyRes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_blog_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      3,
+			NumMessages:   40,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_blog_proto_goTypes,
 		DependencyIndexes: file_proto_blog_proto_depIdxs,
+		EnumInfos:         file_proto_blog_proto_enumTypes,
 		MessageInfos:      file_proto_blog_proto_msgTypes,
 	}.Build()
 	File_proto_blog_proto = out.File
@@ -845,13 +3460,35 @@ const _ = grpc.SupportPackageIsVersion6
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type BlogServiceClient interface {
-	// unary service
+	// Creates a draft blog, its id is generated by the server
 	CreateBlog(ctx context.Context, in *CreateBlogReq, opts ...grpc.CallOption) (*CreateBlogRes, error)
+	// Returns the latest state of a blog, or an older one with revision or as_of
 	ReadBlog(ctx context.Context, in *ReadBlogReq, opts ...grpc.CallOption) (*ReadBlogRes, error)
+	// Changes the fields of a blog named by update_mask, recording a new revision.
+	// Over REST, without an update_mask query parameter only the fields present in the JSON body are updated.
 	UpdateBlog(ctx context.Context, in *UpdateBlogReq, opts ...grpc.CallOption) (*UpdateBlogRes, error)
+	// Moves a blog to the trash
 	DeleteBlog(ctx context.Context, in *DeleteBlogReq, opts ...grpc.CallOption) (*DeleteBlogRes, error)
-	// server streaming - for one request message the server will send back multiple blog messages.
-	ListBlog(ctx context.Context, in *ListBlogReq, opts ...grpc.CallOption) (*ListBlogReq, error)
+	// Brings back the fields of an older revision as a new revision
+	RestoreBlogRevision(ctx context.Context, in *RestoreBlogRevisionReq, opts ...grpc.CallOption) (*RestoreBlogRevisionRes, error)
+	// Takes a blog out of the trash
+	UndeleteBlog(ctx context.Context, in *UndeleteBlogReq, opts ...grpc.CallOption) (*UndeleteBlogRes, error)
+	// Removes a blog in the trash for good, along with its revisions
+	PurgeBlog(ctx context.Context, in *PurgeBlogReq, opts ...grpc.CallOption) (*PurgeBlogRes, error)
+	// Moves a draft blog to review
+	SubmitForReview(ctx context.Context, in *SubmitForReviewReq, opts ...grpc.CallOption) (*SubmitForReviewRes, error)
+	// Publishes a blog in review, now or at publish_at
+	PublishBlog(ctx context.Context, in *PublishBlogReq, opts ...grpc.CallOption) (*PublishBlogRes, error)
+	// Archives a published blog
+	ArchiveBlog(ctx context.Context, in *ArchiveBlogReq, opts ...grpc.CallOption) (*ArchiveBlogRes, error)
+	// Streams the blogs matching the filters, in the requested order
+	ListBlog(ctx context.Context, in *ListBlogReq, opts ...grpc.CallOption) (BlogService_ListBlogClient, error)
+	// Streams the whole history of a blog, oldest revision first
+	ListBlogRevisions(ctx context.Context, in *ListBlogRevisionsReq, opts ...grpc.CallOption) (BlogService_ListBlogRevisionsClient, error)
+	// Streams the content of the trash
+	ListDeletedBlogs(ctx context.Context, in *ListDeletedBlogsReq, opts ...grpc.CallOption) (BlogService_ListDeletedBlogsClient, error)
+	// Streams the best full-text matches of a query, with their matches highlighted
+	SearchBlogs(ctx context.Context, in *SearchBlogsReq, opts ...grpc.CallOption) (BlogService_SearchBlogsClient, error)
 }
 
 type blogServiceClient struct {
@@ -895,27 +3532,222 @@ func (c *blogServiceClient) DeleteBlog(ctx context.Context, in *DeleteBlogReq, o
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *blogServiceClient) RestoreBlogRevision(ctx context.Context, in *RestoreBlogRevisionReq, opts ...grpc.CallOption) (*RestoreBlogRevisionRes, error) {
+	out := new(RestoreBlogRevisionRes)
+	err := c.cc.Invoke(ctx, "/blog.BlogService/RestoreBlogRevision", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) UndeleteBlog(ctx context.Context, in *UndeleteBlogReq, opts ...grpc.CallOption) (*UndeleteBlogRes, error) {
+	out := new(UndeleteBlogRes)
+	err := c.cc.Invoke(ctx, "/blog.BlogService/UndeleteBlog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) PurgeBlog(ctx context.Context, in *PurgeBlogReq, opts ...grpc.CallOption) (*PurgeBlogRes, error) {
+	out := new(PurgeBlogRes)
+	err := c.cc.Invoke(ctx, "/blog.BlogService/PurgeBlog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) SubmitForReview(ctx context.Context, in *SubmitForReviewReq, opts ...grpc.CallOption) (*SubmitForReviewRes, error) {
+	out := new(SubmitForReviewRes)
+	err := c.cc.Invoke(ctx, "/blog.BlogService/SubmitForReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) PublishBlog(ctx context.Context, in *PublishBlogReq, opts ...grpc.CallOption) (*PublishBlogRes, error) {
+	out := new(PublishBlogRes)
+	err := c.cc.Invoke(ctx, "/blog.BlogService/PublishBlog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) ArchiveBlog(ctx context.Context, in *ArchiveBlogReq, opts ...grpc.CallOption) (*ArchiveBlogRes, error) {
+	out := new(ArchiveBlogRes)
+	err := c.cc.Invoke(ctx, "/blog.BlogService/ArchiveBlog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blogServiceClient) ListBlog(ctx context.Context, in *ListBlogReq, opts ...grpc.CallOption) (BlogService_ListBlogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[0], "/blog.BlogService/ListBlog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blogServiceListBlogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlogService_ListBlogClient interface {
+	Recv() (*ListBlogRes, error)
+	grpc.ClientStream
+}
+
+type blogServiceListBlogClient struct {
+	grpc.ClientStream
+}
+
+func (x *blogServiceListBlogClient) Recv() (*ListBlogRes, error) {
+	m := new(ListBlogRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blogServiceClient) ListBlogRevisions(ctx context.Context, in *ListBlogRevisionsReq, opts ...grpc.CallOption) (BlogService_ListBlogRevisionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[1], "/blog.BlogService/ListBlogRevisions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blogServiceListBlogRevisionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlogService_ListBlogRevisionsClient interface {
+	Recv() (*ListBlogRevisionsRes, error)
+	grpc.ClientStream
+}
+
+type blogServiceListBlogRevisionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *blogServiceListBlogRevisionsClient) Recv() (*ListBlogRevisionsRes, error) {
+	m := new(ListBlogRevisionsRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blogServiceClient) ListDeletedBlogs(ctx context.Context, in *ListDeletedBlogsReq, opts ...grpc.CallOption) (BlogService_ListDeletedBlogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[2], "/blog.BlogService/ListDeletedBlogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blogServiceListDeletedBlogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlogService_ListDeletedBlogsClient interface {
+	Recv() (*ListDeletedBlogsRes, error)
+	grpc.ClientStream
+}
+
+type blogServiceListDeletedBlogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *blogServiceListDeletedBlogsClient) Recv() (*ListDeletedBlogsRes, error) {
+	m := new(ListDeletedBlogsRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blogServiceClient) SearchBlogs(ctx context.Context, in *SearchBlogsReq, opts ...grpc.CallOption) (BlogService_SearchBlogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BlogService_serviceDesc.Streams[3], "/blog.BlogService/SearchBlogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blogServiceSearchBlogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-func (c *blogServiceClient) ListBlog(ctx context.Context, in *ListBlogReq, opts ...grpc.CallOption) (*ListBlogReq, error) {
-	out := new(ListBlogReq)
-	err := c.cc.Invoke(ctx, "/blog.BlogService/ListBlog", in, out, opts...)
-	if err != nil {
+type BlogService_SearchBlogsClient interface {
+	Recv() (*SearchBlogsRes, error)
+	grpc.ClientStream
+}
+
+type blogServiceSearchBlogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *blogServiceSearchBlogsClient) Recv() (*SearchBlogsRes, error) {
+	m := new(SearchBlogsRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
 // BlogServiceServer is the server API for BlogService service.
 type BlogServiceServer interface {
-	// unary service
+	// Creates a draft blog, its id is generated by the server
 	CreateBlog(context.Context, *CreateBlogReq) (*CreateBlogRes, error)
+	// Returns the latest state of a blog, or an older one with revision or as_of
 	ReadBlog(context.Context, *ReadBlogReq) (*ReadBlogRes, error)
+	// Changes the fields of a blog named by update_mask, recording a new revision.
+	// Over REST, without an update_mask query parameter only the fields present in the JSON body are updated.
 	UpdateBlog(context.Context, *UpdateBlogReq) (*UpdateBlogRes, error)
+	// Moves a blog to the trash
 	DeleteBlog(context.Context, *DeleteBlogReq) (*DeleteBlogRes, error)
-	// server streaming - for one request message the server will send back multiple blog messages.
-	ListBlog(context.Context, *ListBlogReq) (*ListBlogReq, error)
+	// Brings back the fields of an older revision as a new revision
+	RestoreBlogRevision(context.Context, *RestoreBlogRevisionReq) (*RestoreBlogRevisionRes, error)
+	// Takes a blog out of the trash
+	UndeleteBlog(context.Context, *UndeleteBlogReq) (*UndeleteBlogRes, error)
+	// Removes a blog in the trash for good, along with its revisions
+	PurgeBlog(context.Context, *PurgeBlogReq) (*PurgeBlogRes, error)
+	// Moves a draft blog to review
+	SubmitForReview(context.Context, *SubmitForReviewReq) (*SubmitForReviewRes, error)
+	// Publishes a blog in review, now or at publish_at
+	PublishBlog(context.Context, *PublishBlogReq) (*PublishBlogRes, error)
+	// Archives a published blog
+	ArchiveBlog(context.Context, *ArchiveBlogReq) (*ArchiveBlogRes, error)
+	// Streams the blogs matching the filters, in the requested order
+	ListBlog(*ListBlogReq, BlogService_ListBlogServer) error
+	// Streams the whole history of a blog, oldest revision first
+	ListBlogRevisions(*ListBlogRevisionsReq, BlogService_ListBlogRevisionsServer) error
+	// Streams the content of the trash
+	ListDeletedBlogs(*ListDeletedBlogsReq, BlogService_ListDeletedBlogsServer) error
+	// Streams the best full-text matches of a query, with their matches highlighted
+	SearchBlogs(*SearchBlogsReq, BlogService_SearchBlogsServer) error
 }
 
 // UnimplementedBlogServiceServer can be embedded to have forward compatible implementations.
@@ -934,8 +3766,35 @@ func (*UnimplementedBlogServiceServer) UpdateBlog(context.Context, *UpdateBlogRe
 func (*UnimplementedBlogServiceServer) DeleteBlog(context.Context, *DeleteBlogReq) (*DeleteBlogRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteBlog not implemented")
 }
-func (*UnimplementedBlogServiceServer) ListBlog(context.Context, *ListBlogReq) (*ListBlogReq, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListBlog not implemented")
+func (*UnimplementedBlogServiceServer) RestoreBlogRevision(context.Context, *RestoreBlogRevisionReq) (*RestoreBlogRevisionRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreBlogRevision not implemented")
+}
+func (*UnimplementedBlogServiceServer) UndeleteBlog(context.Context, *UndeleteBlogReq) (*UndeleteBlogRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndeleteBlog not implemented")
+}
+func (*UnimplementedBlogServiceServer) PurgeBlog(context.Context, *PurgeBlogReq) (*PurgeBlogRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeBlog not implemented")
+}
+func (*UnimplementedBlogServiceServer) SubmitForReview(context.Context, *SubmitForReviewReq) (*SubmitForReviewRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitForReview not implemented")
+}
+func (*UnimplementedBlogServiceServer) PublishBlog(context.Context, *PublishBlogReq) (*PublishBlogRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishBlog not implemented")
+}
+func (*UnimplementedBlogServiceServer) ArchiveBlog(context.Context, *ArchiveBlogReq) (*ArchiveBlogRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveBlog not implemented")
+}
+func (*UnimplementedBlogServiceServer) ListBlog(*ListBlogReq, BlogService_ListBlogServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBlog not implemented")
+}
+func (*UnimplementedBlogServiceServer) ListBlogRevisions(*ListBlogRevisionsReq, BlogService_ListBlogRevisionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListBlogRevisions not implemented")
+}
+func (*UnimplementedBlogServiceServer) ListDeletedBlogs(*ListDeletedBlogsReq, BlogService_ListDeletedBlogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListDeletedBlogs not implemented")
+}
+func (*UnimplementedBlogServiceServer) SearchBlogs(*SearchBlogsReq, BlogService_SearchBlogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchBlogs not implemented")
 }
 
 func RegisterBlogServiceServer(s *grpc.Server, srv BlogServiceServer) {
@@ -1014,24 +3873,198 @@ func _BlogService_DeleteBlog_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
-func _BlogService_ListBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListBlogReq)
+func _BlogService_RestoreBlogRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreBlogRevisionReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).RestoreBlogRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.BlogService/RestoreBlogRevision",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).RestoreBlogRevision(ctx, req.(*RestoreBlogRevisionReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_UndeleteBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteBlogReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).UndeleteBlog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.BlogService/UndeleteBlog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).UndeleteBlog(ctx, req.(*UndeleteBlogReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_PurgeBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeBlogReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).PurgeBlog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.BlogService/PurgeBlog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).PurgeBlog(ctx, req.(*PurgeBlogReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_SubmitForReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitForReviewReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).SubmitForReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.BlogService/SubmitForReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).SubmitForReview(ctx, req.(*SubmitForReviewReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_PublishBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishBlogReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlogServiceServer).PublishBlog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.BlogService/PublishBlog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlogServiceServer).PublishBlog(ctx, req.(*PublishBlogReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BlogService_ArchiveBlog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveBlogReq)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(BlogServiceServer).ListBlog(ctx, in)
+		return srv.(BlogServiceServer).ArchiveBlog(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/blog.BlogService/ListBlog",
+		FullMethod: "/blog.BlogService/ArchiveBlog",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(BlogServiceServer).ListBlog(ctx, req.(*ListBlogReq))
+		return srv.(BlogServiceServer).ArchiveBlog(ctx, req.(*ArchiveBlogReq))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BlogService_ListBlog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBlogReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlogServiceServer).ListBlog(m, &blogServiceListBlogServer{stream})
+}
+
+type BlogService_ListBlogServer interface {
+	Send(*ListBlogRes) error
+	grpc.ServerStream
+}
+
+type blogServiceListBlogServer struct {
+	grpc.ServerStream
+}
+
+func (x *blogServiceListBlogServer) Send(m *ListBlogRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlogService_ListBlogRevisions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBlogRevisionsReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlogServiceServer).ListBlogRevisions(m, &blogServiceListBlogRevisionsServer{stream})
+}
+
+type BlogService_ListBlogRevisionsServer interface {
+	Send(*ListBlogRevisionsRes) error
+	grpc.ServerStream
+}
+
+type blogServiceListBlogRevisionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *blogServiceListBlogRevisionsServer) Send(m *ListBlogRevisionsRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlogService_ListDeletedBlogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDeletedBlogsReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlogServiceServer).ListDeletedBlogs(m, &blogServiceListDeletedBlogsServer{stream})
+}
+
+type BlogService_ListDeletedBlogsServer interface {
+	Send(*ListDeletedBlogsRes) error
+	grpc.ServerStream
+}
+
+type blogServiceListDeletedBlogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *blogServiceListDeletedBlogsServer) Send(m *ListDeletedBlogsRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _BlogService_SearchBlogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchBlogsReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlogServiceServer).SearchBlogs(m, &blogServiceSearchBlogsServer{stream})
+}
+
+type BlogService_SearchBlogsServer interface {
+	Send(*SearchBlogsRes) error
+	grpc.ServerStream
+}
+
+type blogServiceSearchBlogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *blogServiceSearchBlogsServer) Send(m *SearchBlogsRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _BlogService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "blog.BlogService",
 	HandlerType: (*BlogServiceServer)(nil),
@@ -1053,10 +4086,267 @@ var _BlogService_serviceDesc = grpc.ServiceDesc{
 			Handler:    _BlogService_DeleteBlog_Handler,
 		},
 		{
-			MethodName: "ListBlog",
-			Handler:    _BlogService_ListBlog_Handler,
+			MethodName: "RestoreBlogRevision",
+			Handler:    _BlogService_RestoreBlogRevision_Handler,
+		},
+		{
+			MethodName: "UndeleteBlog",
+			Handler:    _BlogService_UndeleteBlog_Handler,
+		},
+		{
+			MethodName: "PurgeBlog",
+			Handler:    _BlogService_PurgeBlog_Handler,
+		},
+		{
+			MethodName: "SubmitForReview",
+			Handler:    _BlogService_SubmitForReview_Handler,
+		},
+		{
+			MethodName: "PublishBlog",
+			Handler:    _BlogService_PublishBlog_Handler,
+		},
+		{
+			MethodName: "ArchiveBlog",
+			Handler:    _BlogService_ArchiveBlog_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListBlog",
+			Handler:       _BlogService_ListBlog_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListBlogRevisions",
+			Handler:       _BlogService_ListBlogRevisions_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ListDeletedBlogs",
+			Handler:       _BlogService_ListDeletedBlogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchBlogs",
+			Handler:       _BlogService_SearchBlogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/blog.proto",
+}
+
+// ApiKeyServiceClient is the client API for ApiKeyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ApiKeyServiceClient interface {
+	// Creates a key, its secret is only ever returned by this call and RotateApiKey
+	CreateApiKey(ctx context.Context, in *CreateApiKeyReq, opts ...grpc.CallOption) (*CreateApiKeyRes, error)
+	// Streams the keys of the caller, revoked ones included, without their secrets
+	ListApiKeys(ctx context.Context, in *ListApiKeysReq, opts ...grpc.CallOption) (ApiKeyService_ListApiKeysClient, error)
+	// Revokes a key for good, calls made with it are rejected from then on
+	RevokeApiKey(ctx context.Context, in *RevokeApiKeyReq, opts ...grpc.CallOption) (*RevokeApiKeyRes, error)
+	// Replaces the secret of a key, the previous secret stops working right away
+	RotateApiKey(ctx context.Context, in *RotateApiKeyReq, opts ...grpc.CallOption) (*RotateApiKeyRes, error)
+}
+
+type apiKeyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewApiKeyServiceClient(cc grpc.ClientConnInterface) ApiKeyServiceClient {
+	return &apiKeyServiceClient{cc}
+}
+
+func (c *apiKeyServiceClient) CreateApiKey(ctx context.Context, in *CreateApiKeyReq, opts ...grpc.CallOption) (*CreateApiKeyRes, error) {
+	out := new(CreateApiKeyRes)
+	err := c.cc.Invoke(ctx, "/blog.ApiKeyService/CreateApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiKeyServiceClient) ListApiKeys(ctx context.Context, in *ListApiKeysReq, opts ...grpc.CallOption) (ApiKeyService_ListApiKeysClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ApiKeyService_serviceDesc.Streams[0], "/blog.ApiKeyService/ListApiKeys", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiKeyServiceListApiKeysClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ApiKeyService_ListApiKeysClient interface {
+	Recv() (*ListApiKeysRes, error)
+	grpc.ClientStream
+}
+
+type apiKeyServiceListApiKeysClient struct {
+	grpc.ClientStream
+}
+
+func (x *apiKeyServiceListApiKeysClient) Recv() (*ListApiKeysRes, error) {
+	m := new(ListApiKeysRes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *apiKeyServiceClient) RevokeApiKey(ctx context.Context, in *RevokeApiKeyReq, opts ...grpc.CallOption) (*RevokeApiKeyRes, error) {
+	out := new(RevokeApiKeyRes)
+	err := c.cc.Invoke(ctx, "/blog.ApiKeyService/RevokeApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiKeyServiceClient) RotateApiKey(ctx context.Context, in *RotateApiKeyReq, opts ...grpc.CallOption) (*RotateApiKeyRes, error) {
+	out := new(RotateApiKeyRes)
+	err := c.cc.Invoke(ctx, "/blog.ApiKeyService/RotateApiKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApiKeyServiceServer is the server API for ApiKeyService service.
+type ApiKeyServiceServer interface {
+	// Creates a key, its secret is only ever returned by this call and RotateApiKey
+	CreateApiKey(context.Context, *CreateApiKeyReq) (*CreateApiKeyRes, error)
+	// Streams the keys of the caller, revoked ones included, without their secrets
+	ListApiKeys(*ListApiKeysReq, ApiKeyService_ListApiKeysServer) error
+	// Revokes a key for good, calls made with it are rejected from then on
+	RevokeApiKey(context.Context, *RevokeApiKeyReq) (*RevokeApiKeyRes, error)
+	// Replaces the secret of a key, the previous secret stops working right away
+	RotateApiKey(context.Context, *RotateApiKeyReq) (*RotateApiKeyRes, error)
+}
+
+// UnimplementedApiKeyServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedApiKeyServiceServer struct {
+}
+
+func (*UnimplementedApiKeyServiceServer) CreateApiKey(context.Context, *CreateApiKeyReq) (*CreateApiKeyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApiKey not implemented")
+}
+func (*UnimplementedApiKeyServiceServer) ListApiKeys(*ListApiKeysReq, ApiKeyService_ListApiKeysServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListApiKeys not implemented")
+}
+func (*UnimplementedApiKeyServiceServer) RevokeApiKey(context.Context, *RevokeApiKeyReq) (*RevokeApiKeyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeApiKey not implemented")
+}
+func (*UnimplementedApiKeyServiceServer) RotateApiKey(context.Context, *RotateApiKeyReq) (*RotateApiKeyRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateApiKey not implemented")
+}
+
+func RegisterApiKeyServiceServer(s *grpc.Server, srv ApiKeyServiceServer) {
+	s.RegisterService(&_ApiKeyService_serviceDesc, srv)
+}
+
+func _ApiKeyService_CreateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApiKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiKeyServiceServer).CreateApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.ApiKeyService/CreateApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiKeyServiceServer).CreateApiKey(ctx, req.(*CreateApiKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiKeyService_ListApiKeys_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListApiKeysReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApiKeyServiceServer).ListApiKeys(m, &apiKeyServiceListApiKeysServer{stream})
+}
+
+type ApiKeyService_ListApiKeysServer interface {
+	Send(*ListApiKeysRes) error
+	grpc.ServerStream
+}
+
+type apiKeyServiceListApiKeysServer struct {
+	grpc.ServerStream
+}
+
+func (x *apiKeyServiceListApiKeysServer) Send(m *ListApiKeysRes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ApiKeyService_RevokeApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeApiKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiKeyServiceServer).RevokeApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.ApiKeyService/RevokeApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiKeyServiceServer).RevokeApiKey(ctx, req.(*RevokeApiKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiKeyService_RotateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateApiKeyReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiKeyServiceServer).RotateApiKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/blog.ApiKeyService/RotateApiKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiKeyServiceServer).RotateApiKey(ctx, req.(*RotateApiKeyReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ApiKeyService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "blog.ApiKeyService",
+	HandlerType: (*ApiKeyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateApiKey",
+			Handler:    _ApiKeyService_CreateApiKey_Handler,
+		},
+		{
+			MethodName: "RevokeApiKey",
+			Handler:    _ApiKeyService_RevokeApiKey_Handler,
+		},
+		{
+			MethodName: "RotateApiKey",
+			Handler:    _ApiKeyService_RotateApiKey_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListApiKeys",
+			Handler:       _ApiKeyService_ListApiKeys_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/blog.proto",
 }
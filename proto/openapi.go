@@ -0,0 +1,9 @@
+package blogpb
+
+import _ "embed" // for the OpenAPI document
+
+// OpenAPI is the OpenAPI v3 document of the REST/JSON gateway, in YAML.
+// It is generated from blog.proto along with the code of this package, by make protos.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gopkg.in/yaml.v2"
+)
+
+// Policy is the role based access control of the server, loaded from a YAML file like:
+//
+//	default_roles: [author]
+//	roles:
+//	  author:
+//	    methods: [CreateBlog, ReadBlog, UpdateBlog, DeleteBlog]
+//	  editor:
+//	    methods: ["*"]
+//	    all_blogs: true
+//
+// A caller may call a method when one of its roles lists it, or lists "*" for every method of the BlogService.
+type Policy struct {
+	// DefaultRoles are the roles of the callers whose token has no "roles" claim
+	DefaultRoles []string              `yaml:"default_roles"`
+	Roles        map[string]RolePolicy `yaml:"roles"`
+}
+
+// RolePolicy is what the callers with a role can do
+type RolePolicy struct {
+	// Methods are the names of the BlogService methods the role may call, like "UpdateBlog"
+	Methods []string `yaml:"methods"`
+	// AllBlogs lets the role change the blogs of every author, the others can only change their own
+	AllBlogs bool `yaml:"all_blogs"`
+}
+
+// blogServicePrefix starts the full name of every BlogService method
+const blogServicePrefix = "/" + blogServiceName + "/"
+
+// apiKeyServicePrefix starts the full name of every ApiKeyService method,
+// the policy doesn't cover them: every authenticated user manages their own keys
+const apiKeyServicePrefix = "/blog.ApiKeyService/"
+
+// loadPolicy reads a policy file, roles granting methods the BlogService doesn't have are an error
+func loadPolicy(path string) (*Policy, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the policy file: %w", err)
+	}
+
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(raw, policy); err != nil {
+		return nil, fmt.Errorf("could not decode the policy file %s: %w", path, err)
+	}
+
+	methods := blogpb.File_proto_blog_proto.Services().ByName("BlogService").Methods()
+	var errs []string
+	for role, rp := range policy.Roles {
+		for _, method := range rp.Methods {
+			if method != "*" && methods.ByName(protoreflect.Name(method)) == nil {
+				errs = append(errs, fmt.Sprintf("role %q: unknown method %q", role, method))
+			}
+		}
+	}
+	for _, role := range policy.DefaultRoles {
+		if _, ok := policy.Roles[role]; !ok {
+			errs = append(errs, fmt.Sprintf("default role %q is not defined", role))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid policy file %s:\n  %s", path, strings.Join(errs, "\n  "))
+	}
+	return policy, nil
+}
+
+// roles returns the roles of p, the default ones when its token has none
+func (policy *Policy) roles(p *Principal) []string {
+	if len(p.Roles) == 0 {
+		return policy.DefaultRoles
+	}
+	return p.Roles
+}
+
+// allows tells whether p may call the BlogService method with this short name
+func (policy *Policy) allows(p *Principal, method string) bool {
+	for _, role := range policy.roles(p) {
+		for _, m := range policy.Roles[role].Methods {
+			if m == "*" || m == method {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allBlogs tells whether p may change the blogs of every author, nobody may without a policy
+func (policy *Policy) allBlogs(p *Principal) bool {
+	if policy == nil {
+		return false
+	}
+	for _, role := range policy.roles(p) {
+		if policy.Roles[role].AllBlogs {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize returns PermissionDenied unless the principal of ctx may call fullMethod.
+// It runs after the authentication interceptor, which lets through the unauthenticated methods only.
+func (policy *Policy) authorize(ctx context.Context, fullMethod string) error {
+	if isUnauthenticatedMethod(fullMethod) || strings.HasPrefix(fullMethod, apiKeyServicePrefix) {
+		return nil
+	}
+
+	p := principalFromContext(ctx)
+	if p == nil {
+		return status.Errorf(codes.PermissionDenied, "Access control needs authenticated calls")
+	}
+	method := strings.TrimPrefix(fullMethod, blogServicePrefix)
+	if method == fullMethod || !policy.allows(p, method) {
+		return status.Errorf(codes.PermissionDenied, fmt.Sprintf("%s is not allowed to call %s", p.Subject, fullMethod))
+	}
+	return nil
+}
+
+// unaryInterceptor rejects the calls the policy doesn't allow
+func (policy *Policy) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := policy.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor is unaryInterceptor for the streaming calls
+func (policy *Policy) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := policy.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// checkOwner returns PermissionDenied unless the caller may change the blogs of author:
+// it is author, or one of its roles can change every blog. Everybody may when authentication is disabled.
+func (s *BlogServiceServer) checkOwner(ctx context.Context, author string) error {
+	p := principalFromContext(ctx)
+	if p == nil || p.Subject == author || s.policy.allBlogs(p) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, fmt.Sprintf("%s can't change the blogs of %q", p.Subject, author))
+}
+
+// checkBlogOwner is checkOwner for the author of the blog with this id, as returned by get (the Get or GetDeleted of the store)
+func (s *BlogServiceServer) checkBlogOwner(ctx context.Context, id primitive.ObjectID, get func(context.Context, primitive.ObjectID) (*BlogItem, error)) error {
+	if principalFromContext(ctx) == nil {
+		return nil
+	}
+	data, err := get(ctx, id)
+	if err != nil {
+		return storeError(err, fmt.Sprintf("Could not find blog %s", id.Hex()))
+	}
+	return s.checkOwner(ctx, data.AuthorID)
+}
+
+// editorOf returns the editor to record on a change: the caller, who can't name anybody else.
+// The requested one is trusted when authentication is disabled.
+func editorOf(ctx context.Context, requested string) (string, error) {
+	p := principalFromContext(ctx)
+	if p == nil {
+		return requested, nil
+	}
+	if requested != "" && requested != p.Subject {
+		return "", status.Errorf(codes.PermissionDenied, fmt.Sprintf("editor_id should be blank or %s, got %q", p.Subject, requested))
+	}
+	return p.Subject, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// racingStore changes the blog right after every Get, like another call writing between a check and the write
+type racingStore struct {
+	BlogStore
+}
+
+func (r *racingStore) Get(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	data, err := r.BlogStore.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.BlogStore.Update(ctx, &BlogItem{ID: id, AuthorID: "bob", UpdatedBy: "bob", UpdatedAt: testTime}, []string{"author_id"}, 0); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func TestUpdateBlogChecksTheWrittenVersion(t *testing.T) {
+	store, err := newMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := createBlog(t, store, "alice", "first")
+	s := NewBlogServiceServer(singleTenant(&racingStore{BlogStore: store}), nil)
+
+	// alice owned the blog when it was checked, bob owns it when it is written
+	ctx := withPrincipal(context.Background(), &Principal{Subject: "alice"})
+	_, err = s.UpdateBlog(ctx, &blogpb.UpdateBlogReq{Blog: &blogpb.Blog{Id: created.ID.Hex(), AuthorId: "alice", Title: "mine"}})
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("UpdateBlog returned %v, want Aborted", err)
+	}
+
+	got, err := store.Get(context.Background(), created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AuthorID != "bob" || got.Title != "first" {
+		t.Errorf("the blog of bob became %+v", got)
+	}
+}
+
+// testPolicy lets authors call the methods on their own blogs, and editors change every blog
+var testPolicy = &Policy{
+	DefaultRoles: []string{"author"},
+	Roles: map[string]RolePolicy{
+		"author": {Methods: []string{"CreateBlog", "ReadBlog", "UpdateBlog"}},
+		"editor": {Methods: []string{"*"}, AllBlogs: true},
+	},
+}
+
+func TestCheckOwner(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    *Policy
+		principal *Principal
+		author    string
+		wantCode  codes.Code
+	}{
+		{name: "authentication disabled", policy: testPolicy, principal: nil, author: "alice", wantCode: codes.OK},
+		{name: "owner", policy: testPolicy, principal: &Principal{Subject: "alice"}, author: "alice", wantCode: codes.OK},
+		{name: "other author", policy: testPolicy, principal: &Principal{Subject: "bob"}, author: "alice", wantCode: codes.PermissionDenied},
+		{name: "every author", policy: testPolicy, principal: &Principal{Subject: "bob"}, author: "", wantCode: codes.PermissionDenied},
+		{name: "editor", policy: testPolicy, principal: &Principal{Subject: "bob", Roles: []string{"editor"}}, author: "alice", wantCode: codes.OK},
+		{name: "editor among other roles", policy: testPolicy, principal: &Principal{Subject: "bob", Roles: []string{"author", "editor"}}, author: "alice", wantCode: codes.OK},
+		{name: "undefined role", policy: testPolicy, principal: &Principal{Subject: "bob", Roles: []string{"admin"}}, author: "alice", wantCode: codes.PermissionDenied},
+		{name: "no policy", policy: nil, principal: &Principal{Subject: "bob", Roles: []string{"editor"}}, author: "alice", wantCode: codes.PermissionDenied},
+		{name: "no policy, owner", policy: nil, principal: &Principal{Subject: "alice"}, author: "alice", wantCode: codes.OK},
+		{name: "API key", policy: testPolicy, principal: &Principal{Subject: "alice", APIKeyID: "key"}, author: "alice", wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		s := &BlogServiceServer{policy: tt.policy}
+		ctx := context.Background()
+		if tt.principal != nil {
+			ctx = withPrincipal(ctx, tt.principal)
+		}
+		if err := s.checkOwner(ctx, tt.author); status.Code(err) != tt.wantCode {
+			t.Errorf("%s: checkOwner returned %v, want %v", tt.name, err, tt.wantCode)
+		}
+	}
+}
+
+func TestCheckBlogOwner(t *testing.T) {
+	store, err := newMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := createBlog(t, store, "alice", "first")
+	unavailable := func(context.Context, primitive.ObjectID) (*BlogItem, error) {
+		return nil, errors.New("database unreachable")
+	}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		id        primitive.ObjectID
+		get       func(context.Context, primitive.ObjectID) (*BlogItem, error)
+		wantCode  codes.Code
+	}{
+		// nothing is read when authentication is disabled
+		{name: "authentication disabled", principal: nil, id: created.ID, get: unavailable, wantCode: codes.OK},
+		{name: "owner", principal: &Principal{Subject: "alice"}, id: created.ID, get: store.Get, wantCode: codes.OK},
+		{name: "other author", principal: &Principal{Subject: "bob"}, id: created.ID, get: store.Get, wantCode: codes.PermissionDenied},
+		{name: "editor", principal: &Principal{Subject: "bob", Roles: []string{"editor"}}, id: created.ID, get: store.Get, wantCode: codes.OK},
+		{name: "missing blog", principal: &Principal{Subject: "alice"}, id: primitive.NewObjectID(), get: store.Get, wantCode: codes.NotFound},
+		{name: "not in the trash", principal: &Principal{Subject: "alice"}, id: created.ID, get: store.GetDeleted, wantCode: codes.NotFound},
+		{name: "store failing", principal: &Principal{Subject: "alice"}, id: created.ID, get: unavailable, wantCode: codes.Internal},
+	}
+	s := NewBlogServiceServer(singleTenant(store), testPolicy)
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.principal != nil {
+			ctx = withPrincipal(ctx, tt.principal)
+		}
+		if err := s.checkBlogOwner(ctx, tt.id, tt.get); status.Code(err) != tt.wantCode {
+			t.Errorf("%s: checkBlogOwner returned %v, want %v", tt.name, err, tt.wantCode)
+		}
+	}
+}
+
+func TestPolicyAuthorize(t *testing.T) {
+	author := &Principal{Subject: "alice"}
+	editor := &Principal{Subject: "bob", Roles: []string{"editor"}}
+	tests := []struct {
+		name       string
+		principal  *Principal
+		fullMethod string
+		wantCode   codes.Code
+	}{
+		{"listed method", author, blogServicePrefix + "UpdateBlog", codes.OK},
+		{"unlisted method", author, blogServicePrefix + "DeleteBlog", codes.PermissionDenied},
+		{"every method", editor, blogServicePrefix + "DeleteBlog", codes.OK},
+		{"another service", editor, "/other.Service/DeleteBlog", codes.PermissionDenied},
+		{"own API keys", author, apiKeyServicePrefix + "CreateApiKey", codes.OK},
+		{"health", nil, "/grpc.health.v1.Health/Check", codes.OK},
+		{"unauthenticated", nil, blogServicePrefix + "ReadBlog", codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.principal != nil {
+			ctx = withPrincipal(ctx, tt.principal)
+		}
+		if err := testPolicy.authorize(ctx, tt.fullMethod); status.Code(err) != tt.wantCode {
+			t.Errorf("%s: authorize(%s) returned %v, want %v", tt.name, tt.fullMethod, err, tt.wantCode)
+		}
+	}
+}
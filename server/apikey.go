@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// scopes an API key can be given
+const (
+	scopeBlogsRead  = "blogs:read"
+	scopeBlogsWrite = "blogs:write"
+)
+
+// methodScopes is the scope an API key needs for every BlogService method, the methods missing here can't be called with a key
+var methodScopes = map[string]string{
+	"ReadBlog":          scopeBlogsRead,
+	"ListBlog":          scopeBlogsRead,
+	"ListBlogRevisions": scopeBlogsRead,
+	"ListDeletedBlogs":  scopeBlogsRead,
+	"SearchBlogs":       scopeBlogsRead,
+
+	"CreateBlog":          scopeBlogsWrite,
+	"UpdateBlog":          scopeBlogsWrite,
+	"DeleteBlog":          scopeBlogsWrite,
+	"RestoreBlogRevision": scopeBlogsWrite,
+	"UndeleteBlog":        scopeBlogsWrite,
+	"PurgeBlog":           scopeBlogsWrite,
+	"SubmitForReview":     scopeBlogsWrite,
+	"PublishBlog":         scopeBlogsWrite,
+	"ArchiveBlog":         scopeBlogsWrite,
+}
+
+// apiKeyMetadata is the metadata the secret of an API key is sent in, the "X-Api-Key" header over REST
+const apiKeyMetadata = "x-api-key"
+
+// apiKeySecretPrefix starts every secret, so leaked keys are easy to spot in logs and by secret scanners
+const apiKeySecretPrefix = "bk_"
+
+// newAPIKeySecret returns a random secret, its hash and the prefix shown in listings
+func newAPIKeySecret() (secret, hash, prefix string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	secret = apiKeySecretPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return secret, hashAPIKey(secret), secret[:len(apiKeySecretPrefix)+8], nil
+}
+
+// hashAPIKey is the hash a secret is stored and looked up by.
+// Secrets are 256 random bits, a fast hash is enough: there is nothing to brute force.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyServiceServer implements the gRPC ApiKeyService
+type APIKeyServiceServer struct {
+	keys APIKeyStore
+}
+
+// NewAPIKeyServiceServer returns an APIKeyServiceServer keeping its keys in keys
+func NewAPIKeyServiceServer(keys APIKeyStore) *APIKeyServiceServer {
+	return &APIKeyServiceServer{keys: keys}
+}
+
+// owner returns the principal managing its keys, it must have authenticated with a bearer token
+func (s *APIKeyServiceServer) owner(ctx context.Context) (*Principal, error) {
+	p := principalFromContext(ctx)
+	if p == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "API keys need authentication to be enabled, see auth.jwks_file")
+	}
+	return p, nil
+}
+
+func (s *APIKeyServiceServer) CreateApiKey(ctx context.Context, req *blogpb.CreateApiKeyReq) (*blogpb.CreateApiKeyRes, error) {
+	p, err := s.owner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.GetScopes()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("At least one scope is required: %s or %s", scopeBlogsRead, scopeBlogsWrite))
+	}
+	var scopes []string
+	for _, scope := range req.GetScopes() {
+		if scope != scopeBlogsRead && scope != scopeBlogsWrite {
+			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Unknown scope %q, expected %s or %s", scope, scopeBlogsRead, scopeBlogsWrite))
+		}
+		if !containsField(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	secret, hash, prefix, err := newAPIKeySecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Could not generate a secret: %v", err))
+	}
+
+	// the key keeps the roles its owner has now, so it can never do more than they could
+	created, err := s.keys.CreateAPIKey(ctx, &APIKeyItem{
+		Name:      req.GetName(),
+		Scopes:    scopes,
+		OwnerID:   p.Subject,
+		Roles:     p.Roles,
+		Tenant:    tenantFromContext(ctx),
+		Hash:      hash,
+		Prefix:    prefix,
+		CreatedAt: now(),
+	})
+	if err != nil {
+		return nil, apiKeyError(err, "Could not create the API key")
+	}
+	return &blogpb.CreateApiKeyRes{ApiKey: created.toProto(), Secret: secret}, nil
+}
+
+func (s *APIKeyServiceServer) ListApiKeys(req *blogpb.ListApiKeysReq, stream blogpb.ApiKeyService_ListApiKeysServer) error {
+	p, err := s.owner(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	err = s.keys.ListAPIKeys(stream.Context(), tenantFromContext(stream.Context()), p.Subject, func(key *APIKeyItem) error {
+		return stream.Send(&blogpb.ListApiKeysRes{ApiKey: key.toProto()})
+	})
+	if err != nil {
+		return apiKeyError(err, "Could not list the API keys")
+	}
+	return nil
+}
+
+func (s *APIKeyServiceServer) RevokeApiKey(ctx context.Context, req *blogpb.RevokeApiKeyReq) (*blogpb.RevokeApiKeyRes, error) {
+	oid, err := s.ownKey(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.keys.RevokeAPIKey(ctx, oid, now())
+	if err != nil {
+		return nil, apiKeyError(err, fmt.Sprintf("Could not revoke API key %s", req.GetId()))
+	}
+	return &blogpb.RevokeApiKeyRes{ApiKey: key.toProto()}, nil
+}
+
+func (s *APIKeyServiceServer) RotateApiKey(ctx context.Context, req *blogpb.RotateApiKeyReq) (*blogpb.RotateApiKeyRes, error) {
+	oid, err := s.ownKey(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	secret, hash, prefix, err := newAPIKeySecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Could not generate a secret: %v", err))
+	}
+	key, err := s.keys.RotateAPIKey(ctx, oid, hash, prefix, now())
+	if err != nil {
+		return nil, apiKeyError(err, fmt.Sprintf("Could not rotate API key %s", req.GetId()))
+	}
+	return &blogpb.RotateApiKeyRes{ApiKey: key.toProto(), Secret: secret}, nil
+}
+
+// ownKey returns the ObjectId of a key of the caller, the keys of others (of other tenants too) are reported as missing
+func (s *APIKeyServiceServer) ownKey(ctx context.Context, id string) (primitive.ObjectID, error) {
+	p, err := s.owner(ctx)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
+	}
+	key, err := s.keys.GetAPIKey(ctx, oid)
+	if err == nil && (key.OwnerID != p.Subject || key.Tenant != tenantFromContext(ctx)) {
+		err = ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return primitive.NilObjectID, apiKeyError(err, fmt.Sprintf("Could not find API key %s", id))
+	}
+	return oid, nil
+}
+
+// apiKeyError is storeError for the errors of an APIKeyStore
+func apiKeyError(err error, msg string) error {
+	switch {
+	case errors.Is(err, ErrAPIKeyNotFound):
+		return status.Errorf(codes.NotFound, fmt.Sprintf("%s: %v", msg, err))
+	case errors.Is(err, ErrAPIKeyRevoked):
+		return status.Errorf(codes.FailedPrecondition, fmt.Sprintf("%s: %v", msg, err))
+	default:
+		return storeError(err, msg)
+	}
+}
+
+// apiKeyAuthenticator authenticates the BlogService calls carrying an x-api-key metadata.
+// It runs before the JWT authenticator, which lets through the calls it authenticated.
+type apiKeyAuthenticator struct {
+	keys APIKeyStore
+}
+
+// authenticate returns the principal of the API key of a call to fullMethod, nil when the call has no key
+func (a *apiKeyAuthenticator) authenticate(ctx context.Context, fullMethod string) (*Principal, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(apiKeyMetadata)
+	if len(values) == 0 {
+		return nil, nil
+	}
+	// keys are for the BlogService only, they can't manage keys
+	method := strings.TrimPrefix(fullMethod, blogServicePrefix)
+	if method == fullMethod {
+		return nil, status.Errorf(codes.PermissionDenied, fmt.Sprintf("API keys can't call %s", fullMethod))
+	}
+
+	key, err := a.keys.FindAPIKey(ctx, hashAPIKey(values[0]))
+	if errors.Is(err, ErrAPIKeyNotFound) || (err == nil && key.RevokedAt != nil) {
+		return nil, status.Errorf(codes.Unauthenticated, "Unknown or revoked API key")
+	}
+	if err != nil {
+		return nil, apiKeyError(err, "Could not check the API key")
+	}
+
+	scope := methodScopes[method]
+	if scope == "" || !containsField(key.Scopes, scope) {
+		return nil, status.Errorf(codes.PermissionDenied, fmt.Sprintf("API key %s needs the %s scope to call %s", key.ID.Hex(), scope, method))
+	}
+	return &Principal{Subject: key.OwnerID, Roles: key.Roles, APIKeyID: key.ID.Hex(), Tenant: key.Tenant}, nil
+}
+
+// unaryInterceptor puts the principal of the API key of the call, if any, in the context of the handler
+func (a *apiKeyAuthenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	p, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if p != nil {
+		ctx = withPrincipal(ctx, p)
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor is unaryInterceptor for the streaming calls
+func (a *apiKeyAuthenticator) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	p, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	if p != nil {
+		ss = &contextStream{ServerStream: ss, ctx: withPrincipal(ss.Context(), p)}
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// withAPIKey returns an incoming context carrying the secret of an API key
+func withAPIKey(secret string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyMetadata, secret))
+}
+
+func TestNewAPIKeySecret(t *testing.T) {
+	// the SHA-256 of "abc"
+	if got := hashAPIKey("abc"); got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("hashAPIKey(abc) is %s", got)
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < 10; i++ {
+		secret, hash, prefix, err := newAPIKeySecret()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(secret, apiKeySecretPrefix) || len(secret) != len(apiKeySecretPrefix)+43 {
+			t.Errorf("secret %q should be %s and 43 characters", secret, apiKeySecretPrefix)
+		}
+		if hash != hashAPIKey(secret) || strings.Contains(hash, secret) {
+			t.Errorf("hash of %q is %s, want %s", secret, hash, hashAPIKey(secret))
+		}
+		if prefix != secret[:len(apiKeySecretPrefix)+8] {
+			t.Errorf("prefix of %q is %q", secret, prefix)
+		}
+		if seen[secret] {
+			t.Errorf("secret %q was generated twice", secret)
+		}
+		seen[secret] = true
+	}
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	store, err := newMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := map[string]string{}
+	for _, key := range []struct {
+		name    string
+		scopes  []string
+		revoked bool
+	}{
+		{"reader", []string{scopeBlogsRead}, false},
+		{"writer", []string{scopeBlogsWrite}, false},
+		{"both", []string{scopeBlogsRead, scopeBlogsWrite}, false},
+		{"revoked", []string{scopeBlogsRead, scopeBlogsWrite}, true},
+	} {
+		secret, hash, prefix, err := newAPIKeySecret()
+		if err != nil {
+			t.Fatal(err)
+		}
+		created, err := store.CreateAPIKey(context.Background(), &APIKeyItem{
+			Name: key.name, Scopes: key.scopes, OwnerID: "alice", Roles: []string{"editor"}, Tenant: "acme",
+			Hash: hash, Prefix: prefix, CreatedAt: testTime,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.revoked {
+			if _, err := store.RevokeAPIKey(context.Background(), created.ID, testTime); err != nil {
+				t.Fatal(err)
+			}
+		}
+		secrets[key.name] = secret
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		method   string
+		wantCode codes.Code
+	}{
+		{"read with a read key", secrets["reader"], "ReadBlog", codes.OK},
+		{"search with a read key", secrets["reader"], "SearchBlogs", codes.OK},
+		{"write with a read key", secrets["reader"], "UpdateBlog", codes.PermissionDenied},
+		{"write with a write key", secrets["writer"], "PurgeBlog", codes.OK},
+		{"read with a write key", secrets["writer"], "ListBlog", codes.PermissionDenied},
+		{"both scopes", secrets["both"], "PublishBlog", codes.OK},
+		{"method without a scope", secrets["both"], "DropBlogs", codes.PermissionDenied},
+		{"revoked key", secrets["revoked"], "ReadBlog", codes.Unauthenticated},
+		{"unknown key", apiKeySecretPrefix + "unknown", "ReadBlog", codes.Unauthenticated},
+		// the hash is never a secret
+		{"hash for a secret", hashAPIKey(secrets["reader"]), "ReadBlog", codes.Unauthenticated},
+	}
+	a := &apiKeyAuthenticator{keys: store}
+	for _, tt := range tests {
+		p, err := a.authenticate(withAPIKey(tt.secret), blogServicePrefix+tt.method)
+		if status.Code(err) != tt.wantCode {
+			t.Errorf("%s: authenticate returned %v, want %v", tt.name, err, tt.wantCode)
+			continue
+		}
+		if err == nil && (p.Subject != "alice" || p.Tenant != "acme" || !equalStrings(p.Roles, []string{"editor"}) || p.APIKeyID == "") {
+			t.Errorf("%s: principal is %+v, want the owner of the key", tt.name, p)
+		}
+	}
+
+	// calls without a key are left to the JWT authenticator
+	if p, err := a.authenticate(context.Background(), blogServicePrefix+"ReadBlog"); p != nil || err != nil {
+		t.Errorf("authenticate without a key returned %+v, %v", p, err)
+	}
+	// keys can't manage keys
+	if _, err := a.authenticate(withAPIKey(secrets["both"]), apiKeyServicePrefix+"CreateApiKey"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("authenticate for the ApiKeyService returned %v, want PermissionDenied", err)
+	}
+}
+
+func TestRotateApiKey(t *testing.T) {
+	store, err := newMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewAPIKeyServiceServer(store)
+	a := &apiKeyAuthenticator{keys: store}
+	ctx := withPrincipal(context.Background(), &Principal{Subject: "alice"})
+
+	created, err := s.CreateApiKey(ctx, &blogpb.CreateApiKeyReq{Name: "ci", Scopes: []string{scopeBlogsRead, scopeBlogsRead}})
+	if err != nil {
+		t.Fatalf("CreateApiKey: %v", err)
+	}
+	if !equalStrings(created.GetApiKey().GetScopes(), []string{scopeBlogsRead}) {
+		t.Errorf("scopes are %v, want them once", created.GetApiKey().GetScopes())
+	}
+	rotated, err := s.RotateApiKey(ctx, &blogpb.RotateApiKeyReq{Id: created.GetApiKey().GetId()})
+	if err != nil {
+		t.Fatalf("RotateApiKey: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		wantCode codes.Code
+	}{
+		{"old secret", created.GetSecret(), codes.Unauthenticated},
+		{"new secret", rotated.GetSecret(), codes.OK},
+	}
+	for _, tt := range tests {
+		if _, err := a.authenticate(withAPIKey(tt.secret), blogServicePrefix+"ReadBlog"); status.Code(err) != tt.wantCode {
+			t.Errorf("%s: authenticate returned %v, want %v", tt.name, err, tt.wantCode)
+		}
+	}
+
+	// the keys of others look missing
+	bob := withPrincipal(context.Background(), &Principal{Subject: "bob"})
+	if _, err := s.RevokeApiKey(bob, &blogpb.RevokeApiKeyReq{Id: created.GetApiKey().GetId()}); status.Code(err) != codes.NotFound {
+		t.Errorf("RevokeApiKey of another user returned %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// Principal is who a call is made by, as authenticated by an interceptor
+type Principal struct {
+	// Subject identifies the user, it is the "sub" claim of a JWT
+	Subject string
+	// Roles are the roles of the "roles" claim, checked against the access control policy
+	Roles []string
+	// APIKeyID is the id of the API key the call was made with, blank for a bearer token
+	APIKeyID string
+	// Tenant is the tenant the user belongs to, the "tenant" claim of a JWT
+	Tenant string
+}
+
+type principalKey struct{}
+
+// withPrincipal returns a copy of ctx carrying p, the log of the call records it too
+func withPrincipal(ctx context.Context, p *Principal) context.Context {
+	if l := callLogFromContext(ctx); l != nil {
+		l.principal = p
+	}
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// principalFromContext returns the principal of the call, nil when it is unauthenticated
+func principalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// unauthenticatedMethods can be called without credentials, so orchestrators and tools like grpcurl work without a token
+var unauthenticatedMethods = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+func isUnauthenticatedMethod(fullMethod string) bool {
+	for _, prefix := range unauthenticatedMethods {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// jwtAuthenticator validates the JWT bearer tokens of the calls against the keys of a JWKS file.
+// HS256 tokens are checked with the "oct" keys of the file and RS256 ones with its "RSA" keys.
+type jwtAuthenticator struct {
+	keys     []jose.JSONWebKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+// jwtAlgorithms are the signature algorithms accepted, "none" and the others are rejected
+var jwtAlgorithms = map[string]bool{
+	string(jose.HS256): true,
+	string(jose.RS256): true,
+}
+
+// newJWTAuthenticator loads the keys of a JWKS file ({"keys": [...]})
+func newJWTAuthenticator(cfg AuthConfig) (*jwtAuthenticator, error) {
+	raw, err := ioutil.ReadFile(cfg.JWKSFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the JWKS file: %w", err)
+	}
+
+	set := jose.JSONWebKeySet{}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("could not decode the JWKS file %s: %w", cfg.JWKSFile, err)
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("no key in the JWKS file %s", cfg.JWKSFile)
+	}
+
+	for _, key := range set.Keys {
+		switch key.Key.(type) {
+		case []byte, *rsa.PublicKey:
+		default:
+			return nil, fmt.Errorf("key %q of the JWKS file should be a symmetric (oct) or RSA public key, got %T", key.KeyID, key.Key)
+		}
+		if key.Algorithm != "" && !jwtAlgorithms[key.Algorithm] {
+			return nil, fmt.Errorf("key %q of the JWKS file is for %s, only HS256 and RS256 are supported", key.KeyID, key.Algorithm)
+		}
+	}
+
+	return &jwtAuthenticator{
+		keys:     set.Keys,
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   time.Duration(cfg.Leeway),
+	}, nil
+}
+
+// authenticate returns the principal of the bearer token in the metadata of ctx
+func (a *jwtAuthenticator) authenticate(ctx context.Context) (*Principal, error) {
+	raw, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.ParseSigned(raw)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, fmt.Sprintf("Malformed bearer token: %v", err))
+	}
+	if len(token.Headers) != 1 || !jwtAlgorithms[token.Headers[0].Algorithm] {
+		return nil, status.Errorf(codes.Unauthenticated, "Bearer token should be signed once with HS256 or RS256")
+	}
+	header := token.Headers[0]
+
+	// every key the token may have been signed with is tried, the key id narrows them down when there is one
+	claims := jwt.Claims{}
+	verified := false
+	for _, key := range a.keys {
+		if header.KeyID != "" && key.KeyID != header.KeyID {
+			continue
+		}
+		if key.Algorithm != "" && key.Algorithm != header.Algorithm {
+			continue
+		}
+		// go-jose refuses keys not matching the algorithm of the token, an RSA public key can't verify an HS256 token
+		if err := token.Claims(key.Key, &claims); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, status.Errorf(codes.Unauthenticated, "Bearer token signature is invalid")
+	}
+
+	if claims.Expiry == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Bearer token has no expiry")
+	}
+	expected := jwt.Expected{Issuer: a.issuer, Time: time.Now()}
+	if a.audience != "" {
+		expected.Audience = jwt.Audience{a.audience}
+	}
+	if err := claims.ValidateWithLeeway(expected, a.leeway); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, fmt.Sprintf("Bearer token rejected: %v", err))
+	}
+	if claims.Subject == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "Bearer token has no subject")
+	}
+
+	// the signature is verified already, the other claims can be read as they are
+	extra := struct {
+		Roles  []string `json:"roles"`
+		Tenant string   `json:"tenant"`
+	}{}
+	if err := token.UnsafeClaimsWithoutVerification(&extra); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, fmt.Sprintf("Bearer token claims are invalid, \"roles\" should be a list of strings and %q a string: %v", tenantClaim, err))
+	}
+
+	return &Principal{Subject: claims.Subject, Roles: extra.Roles, Tenant: extra.Tenant}, nil
+}
+
+// bearerToken returns the token of the "authorization: Bearer <token>" metadata
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Errorf(codes.Unauthenticated, "Missing bearer token in the authorization metadata")
+	}
+
+	const prefix = "bearer "
+	if len(values[0]) <= len(prefix) || !strings.EqualFold(values[0][:len(prefix)], prefix) {
+		return "", status.Errorf(codes.Unauthenticated, "Authorization metadata should be \"Bearer <token>\"")
+	}
+	return strings.TrimSpace(values[0][len(prefix):]), nil
+}
+
+// unaryInterceptor rejects the unauthenticated calls, the others reach the handler with their principal in the context.
+// Calls already authenticated by an API key don't need a token.
+func (a *jwtAuthenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isUnauthenticatedMethod(info.FullMethod) || principalFromContext(ctx) != nil {
+		return handler(ctx, req)
+	}
+
+	p, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(withPrincipal(ctx, p), req)
+}
+
+// streamInterceptor is unaryInterceptor for the streaming calls
+func (a *jwtAuthenticator) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isUnauthenticatedMethod(info.FullMethod) || principalFromContext(ss.Context()) != nil {
+		return handler(srv, ss)
+	}
+
+	p, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: withPrincipal(ss.Context(), p)})
+}
+
+// contextStream is a server stream with another context, interceptors use it to pass values to the handlers
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
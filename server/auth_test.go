@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// writeJWKS writes the public part of keys as a JWKS file and returns its path
+func writeJWKS(t *testing.T, keys ...jose.JSONWebKey) string {
+	t.Helper()
+	raw, err := json.Marshal(jose.JSONWebKeySet{Keys: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeConfigFile(t, "jwks.json", string(raw))
+}
+
+// signToken signs claims, with the extra ones, as a compact JWT
+func signToken(t *testing.T, alg jose.SignatureAlgorithm, key interface{}, kid string, claims jwt.Claims, extra map[string]interface{}) string {
+	t.Helper()
+	opts := (&jose.SignerOptions{}).WithType("JWT")
+	if kid != "" {
+		opts = opts.WithHeader("kid", kid)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: key}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := jwt.Signed(signer).Claims(claims).Claims(extra).CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+// withBearer returns an incoming context carrying the authorization metadata
+func withBearer(authorization string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := newJWTAuthenticator(AuthConfig{
+		JWKSFile: writeJWKS(t,
+			jose.JSONWebKey{Key: secret, KeyID: "hs", Algorithm: string(jose.HS256)},
+			jose.JSONWebKey{Key: &rsaKey.PublicKey, KeyID: "rs", Algorithm: string(jose.RS256)},
+		),
+		Issuer:   "https://auth.example.com",
+		Audience: "blog",
+		Leeway:   Duration(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("newJWTAuthenticator: %v", err)
+	}
+
+	now := time.Now()
+	valid := func() jwt.Claims {
+		return jwt.Claims{
+			Subject:  "alice",
+			Issuer:   "https://auth.example.com",
+			Audience: jwt.Audience{"blog"},
+			Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		}
+	}
+	with := func(change func(c *jwt.Claims)) jwt.Claims {
+		c := valid()
+		change(&c)
+		return c
+	}
+	roles := map[string]interface{}{"roles": []string{"editor"}, "tenant": "acme"}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"alice","iss":"https://auth.example.com","aud":"blog","exp":%d}`,
+			now.Add(time.Hour).Unix()))) + "."
+	otherRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantSubject   string // blank when the token is rejected
+		wantErr       string
+	}{
+		{name: "HS256", authorization: "Bearer " + signToken(t, jose.HS256, secret, "hs", valid(), roles), wantSubject: "alice"},
+		{name: "RS256", authorization: "Bearer " + signToken(t, jose.RS256, rsaKey, "rs", valid(), roles), wantSubject: "alice"},
+		{name: "no key id", authorization: "bearer " + signToken(t, jose.HS256, secret, "", valid(), nil), wantSubject: "alice"},
+		{name: "expired within the leeway", authorization: "Bearer " + signToken(t, jose.HS256, secret, "hs", with(func(c *jwt.Claims) {
+			c.Expiry = jwt.NewNumericDate(now.Add(-30 * time.Second))
+		}), nil), wantSubject: "alice"},
+
+		{name: "no token", authorization: "", wantErr: "Missing bearer token"},
+		{name: "basic", authorization: "Basic YWxpY2U6c2VjcmV0", wantErr: "Bearer <token>"},
+		{name: "malformed", authorization: "Bearer not.a.token", wantErr: "Malformed"},
+		{name: "alg none", authorization: "Bearer " + unsigned, wantErr: "HS256 or RS256"},
+		{name: "HS384", authorization: "Bearer " + signToken(t, jose.HS384, secret, "hs", valid(), nil), wantErr: "HS256 or RS256"},
+		{name: "ES256", authorization: "Bearer " + signToken(t, jose.ES256, ecKey, "", valid(), nil), wantErr: "HS256 or RS256"},
+		{name: "wrong secret", authorization: "Bearer " + signToken(t, jose.HS256, []byte("another secret of 32 bytes......"), "hs", valid(), nil), wantErr: "signature"},
+		{name: "wrong RSA key", authorization: "Bearer " + signToken(t, jose.RS256, otherRSA, "rs", valid(), nil), wantErr: "signature"},
+		{name: "key id of another algorithm", authorization: "Bearer " + signToken(t, jose.HS256, secret, "rs", valid(), nil), wantErr: "signature"},
+		{name: "unknown key id", authorization: "Bearer " + signToken(t, jose.HS256, secret, "old", valid(), nil), wantErr: "signature"},
+		{name: "no expiry", authorization: "Bearer " + signToken(t, jose.HS256, secret, "hs", with(func(c *jwt.Claims) { c.Expiry = nil }), nil), wantErr: "no expiry"},
+		{name: "expired", authorization: "Bearer " + signToken(t, jose.HS256, secret, "hs", with(func(c *jwt.Claims) {
+			c.Expiry = jwt.NewNumericDate(now.Add(-2 * time.Minute))
+		}), nil), wantErr: "expired"},
+		{name: "not valid yet", authorization: "Bearer " + signToken(t, jose.HS256, secret, "hs", with(func(c *jwt.Claims) {
+			c.NotBefore = jwt.NewNumericDate(now.Add(10 * time.Minute))
+		}), nil), wantErr: "rejected"},
+		{name: "other issuer", authorization: "Bearer " + signToken(t, jose.HS256, secret, "hs", with(func(c *jwt.Claims) { c.Issuer = "https://evil.example.com" }), nil), wantErr: "rejected"},
+		{name: "other audience", authorization: "Bearer " + signToken(t, jose.HS256, secret, "hs", with(func(c *jwt.Claims) { c.Audience = jwt.Audience{"admin"} }), nil), wantErr: "rejected"},
+		{name: "no subject", authorization: "Bearer " + signToken(t, jose.HS256, secret, "hs", with(func(c *jwt.Claims) { c.Subject = "" }), nil), wantErr: "no subject"},
+		{name: "roles not a list", authorization: "Bearer " + signToken(t, jose.HS256, secret, "hs", valid(), map[string]interface{}{"roles": "admin"}), wantErr: "roles"},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.authorization != "" {
+			ctx = withBearer(tt.authorization)
+		}
+		p, err := a.authenticate(ctx)
+		if tt.wantSubject != "" {
+			if err != nil || p.Subject != tt.wantSubject {
+				t.Errorf("%s: authenticate returned %+v, %v, want %q", tt.name, p, err, tt.wantSubject)
+			}
+			continue
+		}
+		if status.Code(err) != codes.Unauthenticated || !strings.Contains(status.Convert(err).Message(), tt.wantErr) {
+			t.Errorf("%s: authenticate returned %+v, %v, want Unauthenticated mentioning %q", tt.name, p, err, tt.wantErr)
+		}
+	}
+
+	// the other claims are read once the signature is checked
+	p, err := a.authenticate(withBearer("Bearer " + signToken(t, jose.RS256, rsaKey, "rs", valid(), roles)))
+	if err != nil || !equalStrings(p.Roles, []string{"editor"}) || p.Tenant != "acme" {
+		t.Errorf("authenticate returned %+v, %v, want the roles and the tenant of the token", p, err)
+	}
+}
+
+func TestNewJWTAuthenticatorKeys(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		keys    []jose.JSONWebKey
+		wantErr string // blank when the keys are accepted
+	}{
+		{name: "oct key", keys: []jose.JSONWebKey{{Key: []byte("secret"), KeyID: "hs"}}},
+		{name: "no key", keys: nil, wantErr: "no key"},
+		{name: "EC key", keys: []jose.JSONWebKey{{Key: &ecKey.PublicKey, KeyID: "ec"}}, wantErr: "symmetric (oct) or RSA"},
+		{name: "key for HS512", keys: []jose.JSONWebKey{{Key: []byte("secret"), KeyID: "hs", Algorithm: string(jose.HS512)}}, wantErr: "only HS256 and RS256"},
+	}
+	for _, tt := range tests {
+		_, err := newJWTAuthenticator(AuthConfig{JWKSFile: writeJWKS(t, tt.keys...)})
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: newJWTAuthenticator returned %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: newJWTAuthenticator returned %v, want an error mentioning %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfigFile writes a config file named name in a temporary directory and returns its path
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// environment is a lookupEnv reading from a map
+func environment(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	yamlFile := writeConfigFile(t, "blog.yaml", "listen_addr: \":4001\"\nhealth_interval: 1s\nstore: memory\n")
+	tomlFile := writeConfigFile(t, "blog.toml", "listen_addr = \":4002\"\nstore = \"memory\"\n[log]\nlevel = \"debug\"\n")
+
+	tests := []struct {
+		name       string
+		args       []string
+		env        map[string]string
+		wantListen string
+		wantHealth time.Duration
+		wantLevel  string
+	}{
+		{name: "defaults", args: []string{"-store", "memory"}, wantListen: ":4000", wantHealth: 5 * time.Second, wantLevel: "info"},
+		{name: "file", args: []string{"-config", yamlFile}, wantListen: ":4001", wantHealth: time.Second, wantLevel: "info"},
+		{name: "toml file", args: []string{"-config", tomlFile}, wantListen: ":4002", wantHealth: 5 * time.Second, wantLevel: "debug"},
+		{name: "file from the environment", env: map[string]string{"BLOG_CONFIG": yamlFile}, wantListen: ":4001", wantHealth: time.Second, wantLevel: "info"},
+		{
+			name:       "environment over file",
+			args:       []string{"-config", yamlFile},
+			env:        map[string]string{"BLOG_LISTEN_ADDR": ":5000", "BLOG_LOG_LEVEL": "warn"},
+			wantListen: ":5000", wantHealth: time.Second, wantLevel: "warn",
+		},
+		{
+			name:       "flag over environment",
+			args:       []string{"-config", yamlFile, "-listen-addr", ":6000", "-health-interval", "2s"},
+			env:        map[string]string{"BLOG_LISTEN_ADDR": ":5000"},
+			wantListen: ":6000", wantHealth: 2 * time.Second, wantLevel: "info",
+		},
+		{
+			name:       "flag set to its default over environment",
+			args:       []string{"-store", "memory", "-listen-addr", ":4000"},
+			env:        map[string]string{"BLOG_LISTEN_ADDR": ":5000"},
+			wantListen: ":4000", wantHealth: 5 * time.Second, wantLevel: "info",
+		},
+		{
+			name:       "flag naming the file over the environment",
+			args:       []string{"-config", tomlFile},
+			env:        map[string]string{"BLOG_CONFIG": yamlFile},
+			wantListen: ":4002", wantHealth: 5 * time.Second, wantLevel: "debug",
+		},
+	}
+	for _, tt := range tests {
+		cfg, _, err := loadConfig("server", tt.args, environment(tt.env))
+		if err != nil {
+			t.Errorf("%s: loadConfig: %v", tt.name, err)
+			continue
+		}
+		if cfg.ListenAddr != tt.wantListen || time.Duration(cfg.HealthInterval) != tt.wantHealth || cfg.Log.Level != tt.wantLevel {
+			t.Errorf("%s: listen_addr %q, health_interval %v and log.level %q, want %q, %v and %q", tt.name,
+				cfg.ListenAddr, time.Duration(cfg.HealthInterval), cfg.Log.Level, tt.wantListen, tt.wantHealth, tt.wantLevel)
+		}
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "unknown setting", args: []string{"-config", writeConfigFile(t, "typo.yaml", "listen_adr: \":4001\"\n")}, wantErr: "listen_adr"},
+		{name: "unknown toml setting", args: []string{"-config", writeConfigFile(t, "typo.toml", "listen_adr = \":4001\"\n")}, wantErr: "unknown settings"},
+		{name: "unknown extension", args: []string{"-config", writeConfigFile(t, "blog.json", "{}")}, wantErr: "should end with"},
+		{name: "missing file", args: []string{"-config", "/nonexistent/blog.yaml"}, wantErr: "could not read"},
+		{name: "bad environment variable", env: map[string]string{"BLOG_HEALTH_INTERVAL": "soon"}, wantErr: "BLOG_HEALTH_INTERVAL"},
+		{name: "unknown flag", args: []string{"-listen", ":4000"}, wantErr: "-listen"},
+		{name: "arguments", args: []string{"-store", "memory", "extra"}, wantErr: "unexpected arguments"},
+		{name: "invalid setting", args: []string{"-store", "sqlite"}, wantErr: `store "sqlite"`},
+	}
+	for _, tt := range tests {
+		_, _, err := loadConfig("server", tt.args, environment(tt.env))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: loadConfig returned %v, want an error mentioning %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr string // blank when valid
+	}{
+		{name: "defaults", change: func(c *Config) {}},
+		{name: "listen address without a port", change: func(c *Config) { c.ListenAddr = "localhost" }, wantErr: "listen_addr"},
+		{name: "no gateway", change: func(c *Config) { c.HTTPAddr = "" }},
+		{name: "invalid mongo uri", change: func(c *Config) { c.Mongo.URI = "postgres://db" }, wantErr: "mongo.uri"},
+		{name: "same collections", change: func(c *Config) { c.Mongo.RevisionsCollection = c.Mongo.Collection }, wantErr: "should differ"},
+		{name: "bolt without a path", change: func(c *Config) { c.Store, c.BoltPath = "bolt", "" }, wantErr: "bolt_path"},
+		{name: "certificate without a key", change: func(c *Config) { c.TLS.CertFile = "server.pem" }, wantErr: "go together"},
+		{name: "client CAs without TLS", change: func(c *Config) { c.TLS.ClientCAFile = "ca.pem" }, wantErr: "tls.client_ca_file"},
+		{name: "policy without authentication", change: func(c *Config) { c.Auth.PolicyFile = "policy.yaml" }, wantErr: "auth.policy_file"},
+		{name: "unknown tenancy", change: func(c *Config) { c.Tenancy.Mode = "schema" }, wantErr: "tenancy.mode"},
+		{name: "default tenant without tenancy", change: func(c *Config) { c.Tenancy.DefaultTenant = "acme" }, wantErr: "tenancy.default_tenant"},
+		{name: "negative rate", change: func(c *Config) { c.RateLimit.Default.Rate = -1 }, wantErr: "rate_limit.default.rate"},
+		{name: "rate without burst", change: func(c *Config) { c.RateLimit.Default = RateLimit{Rate: 1} }, wantErr: "rate_limit.default.burst"},
+		{name: "unknown method", change: func(c *Config) { c.RateLimit.Methods = map[string]RateLimit{"PostBlog": {Rate: 1, Burst: 1}} }, wantErr: "PostBlog"},
+		{name: "sample ratio over 1", change: func(c *Config) { c.Tracing.Exporter, c.Tracing.SampleRatio = "stdout", 2 }, wantErr: "sample_ratio"},
+		{name: "unknown log level", change: func(c *Config) { c.Log.Level = "trace" }, wantErr: "log.level"},
+		{name: "no shutdown timeout", change: func(c *Config) { c.ShutdownTimeout = 0 }, wantErr: "shutdown_timeout"},
+		{name: "no drain delay", change: func(c *Config) { c.DrainDelay = 0 }},
+	}
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		tt.change(cfg)
+		err := cfg.validate()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: validate returned %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: validate returned %v, want an error mentioning %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// every problem is reported at once
+	cfg := defaultConfig()
+	cfg.ListenAddr, cfg.Log.Level = "localhost", "trace"
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "listen_addr") || !strings.Contains(err.Error(), "log.level") {
+		t.Errorf("validate returned %v, want both problems", err)
+	}
+}
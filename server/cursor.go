@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// listCursor is what hides behind the opaque next_page_token of ListBlogRes.
+// It remembers the ordering of the listing and the sort value and id of the last blog sent,
+// so a listing can carry on right after that blog (keyset pagination, no skip/offset).
+type listCursor struct {
+	SortBy int32  `json:"s"`
+	Desc   bool   `json:"d,omitempty"`
+	Value  string `json:"v,omitempty"`
+	ID     string `json:"id"`
+}
+
+// sortValue returns the value of the field the listing is ordered by, the id is used as a tie breaker
+func sortValue(field blogpb.ListBlogReq_SortField, data *BlogItem) string {
+	switch field {
+	case blogpb.ListBlogReq_TITLE:
+		return data.Title
+	case blogpb.ListBlogReq_AUTHOR_ID:
+		return data.AuthorID
+	default:
+		return ""
+	}
+}
+
+// encodeListCursor builds the token pointing right after data for the ordering asked by req
+func encodeListCursor(req *blogpb.ListBlogReq, data *BlogItem) string {
+	c := listCursor{
+		SortBy: int32(req.GetSortBy()),
+		Desc:   req.GetSortDirection() == blogpb.ListBlogReq_DESC,
+		Value:  sortValue(req.GetSortBy(), data),
+		ID:     data.ID.Hex(),
+	}
+
+	// marshalling a struct of strings, ints and bools can't fail
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeListCursor returns nil if req doesn't resume a previous listing
+func decodeListCursor(req *blogpb.ListBlogReq) (*listCursor, error) {
+	token := req.GetPageToken()
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Malformed page token: %v", err))
+	}
+
+	c := &listCursor{}
+	if err := json.Unmarshal(raw, c); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Malformed page token: %v", err))
+	}
+
+	if _, err := primitive.ObjectIDFromHex(c.ID); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Malformed page token: %v", err))
+	}
+
+	// a token is only meaningful for the ordering it was issued for
+	if c.SortBy != int32(req.GetSortBy()) || c.Desc != (req.GetSortDirection() == blogpb.ListBlogReq_DESC) {
+		return nil, status.Errorf(codes.InvalidArgument, "Page token was issued for a different sort order")
+	}
+
+	return c, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDecodeListCursor(t *testing.T) {
+	byTitle := &blogpb.ListBlogReq{SortBy: blogpb.ListBlogReq_TITLE}
+	last := &BlogItem{ID: primitive.NewObjectID(), Title: "go"}
+	token := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	tests := []struct {
+		name    string
+		req     *blogpb.ListBlogReq
+		want    *listCursor
+		wantErr bool
+	}{
+		{name: "no token", req: byTitle},
+		{
+			name: "token of the same ordering",
+			req:  &blogpb.ListBlogReq{SortBy: blogpb.ListBlogReq_TITLE, PageToken: encodeListCursor(byTitle, last)},
+			want: &listCursor{SortBy: int32(blogpb.ListBlogReq_TITLE), Value: "go", ID: last.ID.Hex()},
+		},
+		{
+			name:    "token of another field",
+			req:     &blogpb.ListBlogReq{SortBy: blogpb.ListBlogReq_AUTHOR_ID, PageToken: encodeListCursor(byTitle, last)},
+			wantErr: true,
+		},
+		{
+			name:    "token of the other direction",
+			req:     &blogpb.ListBlogReq{SortBy: blogpb.ListBlogReq_TITLE, SortDirection: blogpb.ListBlogReq_DESC, PageToken: encodeListCursor(byTitle, last)},
+			wantErr: true,
+		},
+		{name: "not base64", req: &blogpb.ListBlogReq{PageToken: "not a token!"}, wantErr: true},
+		{name: "not json", req: &blogpb.ListBlogReq{PageToken: token("{")}, wantErr: true},
+		{name: "not an id", req: &blogpb.ListBlogReq{PageToken: token(`{"s":0,"id":"42"}`)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := decodeListCursor(tt.req)
+		if tt.wantErr {
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("%s: decodeListCursor returned %v, want InvalidArgument", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: decodeListCursor returned %v", tt.name, err)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("%s: decodeListCursor returned %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// listStream collects what ListBlog sends
+type listStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*blogpb.ListBlogRes
+}
+
+func (s *listStream) Context() context.Context { return s.ctx }
+
+func (s *listStream) Send(res *blogpb.ListBlogRes) error {
+	s.sent = append(s.sent, res)
+	return nil
+}
+
+func TestListBlogPages(t *testing.T) {
+	store, err := newMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"d", "b", "e", "a", "c"} {
+		createBlog(t, store, "alice", title)
+	}
+	s := NewBlogServiceServer(singleTenant(store), nil)
+
+	tests := []struct {
+		name      string
+		direction blogpb.ListBlogReq_SortDirection
+		want      []string
+	}{
+		{"ascending", blogpb.ListBlogReq_ASC, []string{"a", "b", "c", "d", "e"}},
+		{"descending", blogpb.ListBlogReq_DESC, []string{"e", "d", "c", "b", "a"}},
+	}
+	for _, tt := range tests {
+		// pages of 2, each one resuming from the token of the last blog of the previous one
+		var titles []string
+		token := ""
+		for page := 0; page < 4; page++ {
+			stream := &listStream{ctx: context.Background()}
+			req := &blogpb.ListBlogReq{SortBy: blogpb.ListBlogReq_TITLE, SortDirection: tt.direction, PageSize: 2, PageToken: token}
+			if err := s.ListBlog(req, stream); err != nil {
+				t.Fatalf("%s: ListBlog: %v", tt.name, err)
+			}
+			if len(stream.sent) == 0 {
+				break
+			}
+			for _, res := range stream.sent {
+				titles = append(titles, res.GetBlog().GetTitle())
+			}
+			token = stream.sent[len(stream.sent)-1].GetNextPageToken()
+		}
+		if !equalStrings(titles, tt.want) {
+			t.Errorf("%s: the pages listed %v, want %v", tt.name, titles, tt.want)
+		}
+	}
+
+	err = s.ListBlog(&blogpb.ListBlogReq{PageSize: -1}, &listStream{ctx: context.Background()})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListBlog with a negative page size returned %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// gatewayPipeSize is the buffer of the in-process connections between the gateway and the gRPC server
+const gatewayPipeSize = 1 << 20
+
+// newGateway returns the REST/JSON handler of the BlogService and ApiKeyService, along with the listener it must be served on by the gRPC server.
+// It is a reverse proxy: every HTTP request becomes a gRPC call, so the gateway goes through exactly the same code as the gRPC clients.
+// The calls go through an in-process pipe, they never touch the network. The connection is closed when ctx is done.
+func newGateway(ctx context.Context) (http.Handler, net.Listener, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithProtoErrorHandler(httpError),
+		runtime.WithStreamErrorHandler(httpStreamError),
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithOutgoingHeaderMatcher(responseHeaderMatcher),
+	)
+
+	pipe := gatewayListener{bufconn.Listen(gatewayPipeSize)}
+	conn, err := grpc.DialContext(ctx, "gateway",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return pipe.Dial()
+		}),
+	)
+	if err != nil {
+		pipe.Close()
+		return nil, nil, err
+	}
+
+	// both services share the connection
+	for _, register := range []func(context.Context, *runtime.ServeMux, *grpc.ClientConn) error{
+		blogpb.RegisterBlogServiceHandler,
+		blogpb.RegisterApiKeyServiceHandler,
+	} {
+		if err := register(ctx, mux, conn); err != nil {
+			conn.Close()
+			pipe.Close()
+			return nil, nil, err
+		}
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	return mux, pipe, nil
+}
+
+// forwardedHeaders are the headers forwarded as metadata on top of the ones the gateway forwards by default:
+// the API key, the tenant, the request id, and the W3C trace context so the calls continue the traces of the HTTP clients
+var forwardedHeaders = []string{apiKeyMetadata, tenantMetadata, requestIDMetadata, "traceparent", "tracestate", "baggage"}
+
+func headerMatcher(key string) (string, bool) {
+	for _, header := range forwardedHeaders {
+		if strings.EqualFold(key, header) {
+			return header, true
+		}
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// responseHeaderMatcher returns the request id as the X-Request-Id header,
+// the other response metadata gets the Grpc-Metadata- prefix the gateway gives it by default
+func responseHeaderMatcher(key string) (string, bool) {
+	if key == requestIDMetadata {
+		return "X-Request-Id", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// gatewayListener accepts the connections of the gateway, marked so the server can tell them apart
+type gatewayListener struct {
+	*bufconn.Listener
+}
+
+type gatewayConn struct {
+	net.Conn
+}
+
+// RemoteAddr tells the calls of the gateway apart, the address of their HTTP client is in the x-forwarded-for metadata
+func (c gatewayConn) RemoteAddr() net.Addr {
+	return gatewayAddr{}
+}
+
+// gatewayAddr is the peer address of the calls of the gateway
+type gatewayAddr struct{}
+
+func (gatewayAddr) Network() string { return "gateway" }
+func (gatewayAddr) String() string  { return "gateway" }
+
+func (l gatewayListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return gatewayConn{conn}, nil
+}
+
+// isGatewayConn tells whether a connection accepted by the gRPC server comes from the gateway
+func isGatewayConn(conn net.Conn) bool {
+	_, ok := conn.(gatewayConn)
+	return ok
+}
+
+// httpStatusFromCode is the HTTP status of the responses failing with a gRPC code.
+// It is the usual mapping (NotFound is 404, InvalidArgument 400, Aborted 409...), except for FailedPrecondition:
+// the server only returns it when a blog isn't in the right workflow status, which is a conflict with the state of the blog, not a bad request.
+func httpStatusFromCode(code codes.Code) int {
+	if code == codes.FailedPrecondition {
+		return http.StatusConflict
+	}
+	return runtime.HTTPStatusFromCode(code)
+}
+
+// httpError replies to a failed unary call with its status as JSON, details included
+func httpError(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	code := codes.Unknown
+	if s, ok := status.FromError(err); ok {
+		code = s.Code()
+	}
+	httpStatus := httpStatusFromCode(code)
+	if delay := retryDelay(err); delay > 0 {
+		// whole seconds, rounded up so a client waiting this long is let through
+		w.Header().Set("Retry-After", strconv.Itoa(int((delay+time.Second-1)/time.Second)))
+	}
+	// the gateway reports paths matching no rpc as Unimplemented, like gRPC does for unknown methods
+	if err == runtime.ErrUnknownURI {
+		httpStatus = http.StatusNotFound
+	}
+	runtime.DefaultHTTPProtoErrorHandler(ctx, mux, marshaler, &statusWriter{ResponseWriter: w, status: httpStatus}, r, err)
+}
+
+// retryDelay returns the delay of the RetryInfo detail of a status error, 0 when it has none
+func retryDelay(err error) time.Duration {
+	s, ok := status.FromError(err)
+	if !ok {
+		return 0
+	}
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration()
+		}
+	}
+	return 0
+}
+
+// httpStreamError is the last object of a failed streaming call,
+// its status is also the one of the response when nothing was streamed before the error
+func httpStreamError(ctx context.Context, err error) *runtime.StreamError {
+	serr := runtime.DefaultHTTPStreamErrorHandler(ctx, err)
+	serr.HttpCode = int32(httpStatusFromCode(codes.Code(serr.GrpcCode)))
+	serr.HttpStatus = http.StatusText(int(serr.HttpCode))
+	return serr
+}
+
+// statusWriter replaces the status written by the default error handler with its own
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(int) {
+	w.ResponseWriter.WriteHeader(w.status)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestHTTPError(t *testing.T) {
+	rateLimited, _ := status.New(codes.ResourceExhausted, "too many calls").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(1200 * time.Millisecond)})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		retryAfter string
+	}{
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad id"), http.StatusBadRequest, ""},
+		{"not found", status.Error(codes.NotFound, "no blog"), http.StatusNotFound, ""},
+		{"stale version", status.Error(codes.Aborted, "version mismatch"), http.StatusConflict, ""},
+		{"wrong status", status.Error(codes.FailedPrecondition, "not a draft"), http.StatusConflict, ""},
+		{"unauthenticated", status.Error(codes.Unauthenticated, "no token"), http.StatusUnauthorized, ""},
+		{"permission denied", status.Error(codes.PermissionDenied, "not yours"), http.StatusForbidden, ""},
+		{"rate limited", rateLimited.Err(), http.StatusTooManyRequests, "2"},
+		{"database unreachable", status.Error(codes.Unavailable, "no mongo"), http.StatusServiceUnavailable, ""},
+		{"unknown path", runtime.ErrUnknownURI, http.StatusNotFound, ""},
+		{"not a status", errors.New("boom"), http.StatusInternalServerError, ""},
+	}
+	mux := runtime.NewServeMux()
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/v1/blogs", nil)
+		httpError(context.Background(), mux, &runtime.JSONPb{}, w, r, tt.err)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status is %d, want %d", tt.name, w.Code, tt.wantStatus)
+		}
+		if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+			t.Errorf("%s: Retry-After is %q, want %q", tt.name, got, tt.retryAfter)
+		}
+	}
+}
+
+func TestHTTPStreamError(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want int
+	}{
+		{codes.FailedPrecondition, http.StatusConflict},
+		{codes.NotFound, http.StatusNotFound},
+		{codes.Internal, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		serr := httpStreamError(context.Background(), status.Error(tt.code, "failed"))
+		if serr.HttpCode != int32(tt.want) || serr.HttpStatus != http.StatusText(tt.want) {
+			t.Errorf("%v: stream error is %d %q, want %d", tt.code, serr.HttpCode, serr.HttpStatus, tt.want)
+		}
+	}
+}
+
+func TestHeaderMatcher(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{"X-Api-Key", apiKeyMetadata, true},
+		{"X-Tenant-Id", tenantMetadata, true},
+		{"Traceparent", "traceparent", true},
+		// the gateway forwards the bearer token as authorization on its own
+		{"Authorization", "grpcgateway-Authorization", true},
+		{"Grpc-Metadata-Foo", "Foo", true},
+		{"X-Custom", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := headerMatcher(tt.header); got != tt.want || ok != tt.ok {
+			t.Errorf("headerMatcher(%q) returned %q, %v, want %q, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
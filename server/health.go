@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// blogServiceName is the name BlogService is known by in health checks, the server as a whole is ""
+const blogServiceName = "blog.BlogService"
+
+// setServing reports the whole server and BlogService as serving or not
+func setServing(hs *health.Server, serving bool) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !serving {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	hs.SetServingStatus("", status)
+	hs.SetServingStatus(blogServiceName, status)
+}
+
+// runHealthCheck calls check every interval until ctx is done and reports its result on the health service,
+// so probes see NOT_SERVING while the database is unreachable and SERVING again once it is back
+func runHealthCheck(ctx context.Context, hs *health.Server, interval time.Duration, check func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		// a check taking longer than the interval counts as failed
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		err := check(checkCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		// only changes are reported, the health service notifies the watchers of every update
+		switch {
+		case err != nil && healthy:
+			zap.L().Warn("Health check failed, now NOT_SERVING", zap.Error(err))
+			setServing(hs, false)
+		case err == nil && !healthy:
+			zap.L().Info("Health check passed, now SERVING")
+			setServing(hs, true)
+		}
+		healthy = err == nil
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
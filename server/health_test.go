@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// servingStatus returns the status reported for a service, as a probe would get it
+func servingStatus(t *testing.T, hs *health.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	res, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q): %v", service, err)
+	}
+	return res.GetStatus()
+}
+
+func TestSetServing(t *testing.T) {
+	hs := health.NewServer()
+	for _, serving := range []bool{false, true, false} {
+		setServing(hs, serving)
+		want := healthpb.HealthCheckResponse_NOT_SERVING
+		if serving {
+			want = healthpb.HealthCheckResponse_SERVING
+		}
+		for _, service := range []string{"", blogServiceName} {
+			if got := servingStatus(t, hs, service); got != want {
+				t.Errorf("after setServing(%v), %q is %v, want %v", serving, service, got, want)
+			}
+		}
+	}
+}
+
+func TestRunHealthCheck(t *testing.T) {
+	down := errors.New("database unreachable")
+	tests := []struct {
+		err  error
+		want healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{nil, healthpb.HealthCheckResponse_SERVING},
+		{down, healthpb.HealthCheckResponse_NOT_SERVING},
+		{down, healthpb.HealthCheckResponse_NOT_SERVING},
+		{nil, healthpb.HealthCheckResponse_SERVING},
+		{down, healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+
+	hs := health.NewServer()
+	setServing(hs, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	checks := 0
+	runHealthCheck(ctx, hs, time.Millisecond, func(context.Context) error {
+		// the status is reported before the next check, which looks at the one of the previous check
+		if checks > 0 {
+			if got := servingStatus(t, hs, blogServiceName); got != tests[checks-1].want {
+				t.Errorf("after check %d, the status is %v, want %v", checks, got, tests[checks-1].want)
+			}
+		}
+		if checks == len(tests) {
+			cancel()
+			return nil
+		}
+		checks++
+		return tests[checks-1].err
+	})
+	if checks != len(tests) {
+		t.Errorf("runHealthCheck returned after %d checks, want %d", checks, len(tests))
+	}
+}
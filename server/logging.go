@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// requestIDMetadata identifies a call in the logs, the "X-Request-Id" header over REST.
+// Clients may send their own id to find their calls, the server makes one up otherwise, and it is sent back in the response headers.
+const requestIDMetadata = "x-request-id"
+
+// requestIDPattern is what the ids sent by the clients must look like, the others are replaced so they can't forge log lines
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// redactedMark replaces the values hidden from the logs
+const redactedMark = "[REDACTED]"
+
+// secretFields are never logged, they are the secrets of the API keys
+var secretFields = map[protoreflect.Name]bool{"secret": true}
+
+// contentFields are hidden from the logs when the content of the blogs is redacted
+var contentFields = map[protoreflect.Name]bool{"content": true, "content_snippet": true}
+
+// newLogger returns the logger of the server, writing JSON lines to stderr from the level of cfg on
+func newLogger(cfg LogConfig) (*zap.Logger, error) {
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return nil, err
+	}
+
+	zcfg := zap.NewProductionConfig()
+	zcfg.Level = zap.NewAtomicLevelAt(level)
+	zcfg.EncoderConfig.TimeKey = "time"
+	zcfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	zcfg.EncoderConfig.EncodeDuration = zapcore.StringDurationEncoder
+	// every call is logged, none is sampled away, and the failed ones don't need the stack of the interceptor
+	zcfg.Sampling = nil
+	zcfg.DisableStacktrace = true
+	return zcfg.Build()
+}
+
+// tenantField names a tenant in the logs, it is skipped when there is a single tenant
+func tenantField(tenant string) zap.Field {
+	if tenant == "" {
+		return zap.Skip()
+	}
+	return zap.String("tenant", tenant)
+}
+
+type callLogKey struct{}
+
+// callLog is what the interceptors after the logging one find out about a call: they pass it to the handler
+// in contexts of their own, so they record it here too for the logging interceptor
+type callLog struct {
+	principal *Principal
+	tenant    string
+}
+
+// callLogFromContext returns the callLog of the call, nil outside of the logging interceptor
+func callLogFromContext(ctx context.Context) *callLog {
+	l, _ := ctx.Value(callLogKey{}).(*callLog)
+	return l
+}
+
+// callLogger logs every call once it is handled: its method, client, principal, duration and status code
+type callLogger struct {
+	logger *zap.Logger
+	// redactContent hides the content of the blogs from the messages logged at debug level
+	redactContent bool
+}
+
+func newCallLogger(logger *zap.Logger, redactContent bool) *callLogger {
+	return &callLogger{logger: logger, redactContent: redactContent}
+}
+
+// requestID returns the id of the call sent by the client, or a new one when it sent none or a malformed one
+func requestID(md metadata.MD) string {
+	if values := md.Get(requestIDMetadata); len(values) > 0 && requestIDPattern.MatchString(values[0]) {
+		return values[0]
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// start gives the call its request id, in the incoming metadata for the handler, and its callLog
+func (l *callLogger) start(ctx context.Context) (context.Context, string, *callLog) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	id := requestID(md)
+	md.Set(requestIDMetadata, id)
+
+	entry := &callLog{}
+	ctx = context.WithValue(metadata.NewIncomingContext(ctx, md), callLogKey{}, entry)
+	return ctx, id, entry
+}
+
+// fields are the fields of the log lines of a call
+func (l *callLogger) fields(ctx context.Context, fullMethod, id string) []zap.Field {
+	fields := []zap.Field{
+		zap.String("request_id", id),
+		zap.String("method", fullMethod),
+		zap.String("peer", peerAddress(ctx)),
+	}
+	// the spans of the call are found from its log lines
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields, zap.String("trace_id", sc.TraceID().String()))
+	}
+	return fields
+}
+
+// done logs a handled call at the level of its status code, health checks and reflection at debug level only
+func (l *callLogger) done(logger *zap.Logger, fullMethod string, entry *callLog, start time.Time, err error, extra ...zap.Field) {
+	code := status.Code(err)
+	level := callLevel(code)
+	if isUnauthenticatedMethod(fullMethod) {
+		level = zapcore.DebugLevel
+	}
+	ce := logger.Check(level, "Handled call")
+	if ce == nil {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("code", code.String()),
+		zap.Float64("duration_ms", float64(time.Since(start))/float64(time.Millisecond)),
+	}
+	if p := entry.principal; p != nil {
+		fields = append(fields, zap.String("principal", p.Subject))
+		if p.APIKeyID != "" {
+			fields = append(fields, zap.String("api_key", p.APIKeyID))
+		}
+	}
+	fields = append(fields, tenantField(entry.tenant))
+	if err != nil {
+		fields = append(fields, zap.String("error", status.Convert(err).Message()))
+	}
+	ce.Write(append(fields, extra...)...)
+}
+
+// callLevel is the level of the calls ending with code: the mistakes of the clients are info, what an operator
+// may have to look at is warn, and the failures of the server are error
+func callLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return zapcore.InfoLevel
+	case codes.DeadlineExceeded, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition,
+		codes.Aborted, codes.OutOfRange, codes.Unavailable:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// payload is a message of a call as JSON, without the secrets and, when redacted, the content of the blogs
+func (l *callLogger) payload(key string, m interface{}) zap.Field {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return zap.Skip()
+	}
+	msg = proto.Clone(msg)
+	redact(msg.ProtoReflect(), l.redactContent)
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return zap.String(key, fmt.Sprintf("could not encode the message: %v", err))
+	}
+	return zap.Reflect(key, json.RawMessage(b))
+}
+
+// redact replaces the secrets of m, and the content of the blogs when content is true, with redactedMark
+func redact(m protoreflect.Message, content bool) {
+	var hidden []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.Kind() == protoreflect.StringKind && !fd.IsList() && !fd.IsMap():
+			if secretFields[fd.Name()] || (content && contentFields[fd.Name()]) {
+				hidden = append(hidden, fd)
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+					redact(value.Message(), content)
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i, list := 0, v.List(); i < list.Len(); i++ {
+					redact(list.Get(i).Message(), content)
+				}
+			}
+		case fd.Message() != nil:
+			redact(v.Message(), content)
+		}
+		return true
+	})
+	// the fields are set once the range is over, it must not change the message
+	for _, fd := range hidden {
+		m.Set(fd, protoreflect.ValueOfString(redactedMark))
+	}
+}
+
+// unaryInterceptor logs every call, with its request and response at debug level.
+// It runs right after the metrics and the tracing, so the calls the other interceptors reject are logged too.
+func (l *callLogger) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	ctx, id, entry := l.start(ctx)
+	// set now but sent with the response, the handler may still add headers of its own
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, id))
+
+	resp, err := handler(ctx, req)
+
+	logger := l.logger.With(l.fields(ctx, info.FullMethod, id)...)
+	var payloads []zap.Field
+	if logger.Core().Enabled(zapcore.DebugLevel) {
+		payloads = append(payloads, l.payload("request", req))
+		if err == nil {
+			payloads = append(payloads, l.payload("response", resp))
+		}
+	}
+	l.done(logger, info.FullMethod, entry, start, err, payloads...)
+	return resp, err
+}
+
+// streamInterceptor is unaryInterceptor for the streaming calls, their messages are logged one by one at debug level
+func (l *callLogger) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx, id, entry := l.start(ss.Context())
+	ss.SetHeader(metadata.Pairs(requestIDMetadata, id))
+
+	logger := l.logger.With(l.fields(ctx, info.FullMethod, id)...)
+	var wrapped grpc.ServerStream = &contextStream{ServerStream: ss, ctx: ctx}
+	if logger.Core().Enabled(zapcore.DebugLevel) {
+		wrapped = &loggedStream{ServerStream: wrapped, logger: logger, calls: l}
+	}
+
+	err := handler(srv, wrapped)
+	l.done(logger, info.FullMethod, entry, start, err)
+	return err
+}
+
+// loggedStream logs the messages of a stream at debug level
+type loggedStream struct {
+	grpc.ServerStream
+	logger *zap.Logger
+	calls  *callLogger
+}
+
+func (s *loggedStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.logger.Debug("Received message", s.calls.payload("message", m))
+	}
+	return err
+}
+
+func (s *loggedStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.logger.Debug("Sent message", s.calls.payload("message", m))
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRedact(t *testing.T) {
+	blog := &blogpb.Blog{Id: "1", AuthorId: "alice", Title: "hello", Content: "private draft"}
+	tests := []struct {
+		name    string
+		msg     proto.Message
+		content bool
+		hidden  []string // values that must not be logged
+		kept    []string // values that must be
+	}{
+		{
+			name:   "new API key",
+			msg:    &blogpb.CreateApiKeyRes{ApiKey: &blogpb.ApiKey{Name: "ci", Prefix: "bk_abcdefgh"}, Secret: "bk_abcdefgh-the-rest"},
+			hidden: []string{"bk_abcdefgh-the-rest"}, kept: []string{"ci", "bk_abcdefgh\""},
+		},
+		{
+			name:   "rotated API key",
+			msg:    &blogpb.RotateApiKeyRes{Secret: "bk_rotated"},
+			hidden: []string{"bk_rotated"},
+		},
+		{
+			name:   "content kept",
+			msg:    &blogpb.ReadBlogRes{Blog: blog},
+			kept:   []string{"private draft", "hello"},
+			hidden: nil,
+		},
+		{
+			name:    "content redacted",
+			msg:     &blogpb.ReadBlogRes{Blog: blog},
+			content: true,
+			hidden:  []string{"private draft"}, kept: []string{"hello", "alice"},
+		},
+		{
+			name:    "search result redacted",
+			msg:     &blogpb.SearchBlogsRes{Blog: blog, TitleSnippet: "<mark>hello</mark>", ContentSnippet: "…private <mark>draft</mark>…"},
+			content: true,
+			hidden:  []string{"private draft", "private <mark>draft"}, kept: []string{"<mark>hello</mark>"},
+		},
+		{
+			// a blank secret is not set, there is nothing to hide
+			name: "no secret",
+			msg:  &blogpb.CreateApiKeyRes{ApiKey: &blogpb.ApiKey{Name: "ci"}},
+			kept: []string{"ci"},
+		},
+	}
+	for _, tt := range tests {
+		before := proto.Clone(tt.msg)
+		l := newCallLogger(zap.NewNop(), tt.content)
+		field := l.payload("response", tt.msg)
+		logged := string(field.Interface.(json.RawMessage))
+
+		for _, value := range tt.hidden {
+			if strings.Contains(logged, value) {
+				t.Errorf("%s: %s is logged in %s", tt.name, value, logged)
+			}
+		}
+		for _, value := range tt.kept {
+			if !strings.Contains(logged, value) {
+				t.Errorf("%s: %s is missing from %s", tt.name, value, logged)
+			}
+		}
+		if len(tt.hidden) > 0 && !strings.Contains(logged, redactedMark) {
+			t.Errorf("%s: %s doesn't show what was redacted", tt.name, logged)
+		}
+		// the message sent to the client keeps its values
+		if !proto.Equal(tt.msg, before) {
+			t.Errorf("%s: payload changed the message to %v", tt.name, tt.msg)
+		}
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		sent []string
+		keep bool
+	}{
+		{"none", nil, false},
+		{"uuid", []string{"0f8fad5b-d9cb-469f-a165-70867728950e"}, true},
+		{"trace like", []string{"svc.web:1234_a"}, true},
+		{"forged log line", []string{"abc\n{\"level\":\"error\"}"}, false},
+		{"blank", []string{""}, false},
+		{"too long", []string{strings.Repeat("a", 129)}, false},
+	}
+	for _, tt := range tests {
+		md := metadata.MD{}
+		if tt.sent != nil {
+			md = metadata.Pairs(requestIDMetadata, tt.sent[0])
+		}
+		id := requestID(md)
+		if kept := tt.sent != nil && id == tt.sent[0]; kept != tt.keep {
+			t.Errorf("%s: requestID returned %q, want the sent id kept: %v", tt.name, id, tt.keep)
+		}
+		if !requestIDPattern.MatchString(id) {
+			t.Errorf("%s: requestID returned %q", tt.name, id)
+		}
+	}
+}
+
+func TestCallLoggerUnary(t *testing.T) {
+	secret := "bk_0123456789abcdefghijklmnopqrstuvwxyzABCDEFG"
+	tests := []struct {
+		name      string
+		level     zapcore.Level
+		err       error
+		wantLevel zapcore.Level
+		wantCode  string
+		logged    bool
+	}{
+		{"success", zapcore.DebugLevel, nil, zapcore.InfoLevel, "OK", true},
+		{"client mistake", zapcore.DebugLevel, status.Error(codes.InvalidArgument, "bad scope"), zapcore.InfoLevel, "InvalidArgument", true},
+		{"refused", zapcore.DebugLevel, status.Error(codes.PermissionDenied, "not yours"), zapcore.WarnLevel, "PermissionDenied", true},
+		{"server failure", zapcore.DebugLevel, status.Error(codes.Internal, "broken"), zapcore.ErrorLevel, "Internal", true},
+		{"below the level", zapcore.WarnLevel, nil, zapcore.InfoLevel, "OK", false},
+	}
+	for _, tt := range tests {
+		core, logs := observer.New(tt.level)
+		l := newCallLogger(zap.New(core), false)
+		info := &grpc.UnaryServerInfo{FullMethod: apiKeyServicePrefix + "CreateApiKey"}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			withPrincipal(ctx, &Principal{Subject: "alice"})
+			if tt.err != nil {
+				return nil, tt.err
+			}
+			return &blogpb.CreateApiKeyRes{ApiKey: &blogpb.ApiKey{Name: "ci"}, Secret: secret}, nil
+		}
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDMetadata, "req-1"))
+		if _, err := l.unaryInterceptor(ctx, &blogpb.CreateApiKeyReq{Name: "ci"}, info, handler); err != tt.err {
+			t.Errorf("%s: the interceptor returned %v, want %v", tt.name, err, tt.err)
+		}
+
+		entries := logs.All()
+		if !tt.logged {
+			if len(entries) != 0 {
+				t.Errorf("%s: logged %v", tt.name, entries)
+			}
+			continue
+		}
+		if len(entries) != 1 {
+			t.Errorf("%s: logged %d lines, want 1", tt.name, len(entries))
+			continue
+		}
+		entry := entries[0]
+		fields := entry.ContextMap()
+		if entry.Level != tt.wantLevel || fields["code"] != tt.wantCode || fields["request_id"] != "req-1" || fields["principal"] != "alice" {
+			t.Errorf("%s: logged %v %v, want %v and code %s", tt.name, entry.Level, fields, tt.wantLevel, tt.wantCode)
+		}
+		for key, value := range fields {
+			var text string
+			if raw, ok := value.(json.RawMessage); ok {
+				text = string(raw)
+			} else if b, err := json.Marshal(value); err == nil {
+				text = string(b)
+			}
+			if strings.Contains(text, secret) {
+				t.Errorf("%s: the secret is logged in %s", tt.name, key)
+			}
+		}
+		if tt.err == nil {
+			if _, ok := fields["response"]; !ok {
+				t.Errorf("%s: the response is not logged at debug level", tt.name)
+			}
+		}
+	}
+}
@@ -2,62 +2,103 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	blogpb "github.com/vaibhav/assignment1/proto"
 
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var db *mongo.Client
-var blogdb *mongo.Collection
 var mongoCtx context.Context
 
-type BlogServiceServer struct{}
+// BlogServiceServer implements the gRPC BlogService on top of any BlogStore, one per tenant
+type BlogServiceServer struct {
+	// stores holds the store of every tenant, handlers only get the one of the tenant of the call (see storeOf)
+	stores *tenantStores
+	// policy says which callers may change the blogs of others, nil when there is no access control
+	policy *Policy
+}
+
+// NewBlogServiceServer returns a BlogServiceServer keeping the blogs of every tenant in its store, policy may be nil
+func NewBlogServiceServer(stores *tenantStores, policy *Policy) *BlogServiceServer {
+	return &BlogServiceServer{stores: stores, policy: policy}
+}
 
 // In the function bodies we’ll generally use the following workflow:
-// Protbuf Message (Request) → Regular Go Struct → Convert to BSON + Mongo Action → Protobuf Message (Response)
+// Protbuf Message (Request) → Regular Go Struct → Store Action → Protobuf Message (Response)
 
 func (s *BlogServiceServer) CreateBlog(ctx context.Context, req *blogpb.CreateBlogReq) (*blogpb.CreateBlogRes, error) {
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	//  First we’ll extract the Blog message from our request message and convert it to a regular go struct
 	blog := req.GetBlog()
 
-	// convert it into BlogItem type to convert into BSON
-	data := BlogItem{
-		// ID:    Empty, so it gets omitted and MongoDB generates a unique Object ID upon insertion.
-		AuthorID: blog.GetAuthorId(),
-		Title:    blog.GetTitle(),
-		Content:  blog.GetContent(),
+	// authenticated callers write their own blogs, the author defaults to them and they are the editor of the first revision
+	author, editor := blog.GetAuthorId(), blog.GetAuthorId()
+	if p := principalFromContext(ctx); p != nil {
+		if author == "" {
+			author = p.Subject
+		}
+		editor = p.Subject
+	}
+	if err := s.checkOwner(ctx, author); err != nil {
+		return nil, err
+	}
+
+	// convert it into BlogItem type, ID is left empty and gets generated by the store
+	data := &BlogItem{
+		AuthorID:  author,
+		Title:     blog.GetTitle(),
+		Content:   blog.GetContent(),
+		UpdatedBy: editor,
+		UpdatedAt: now(),
+		Status:    statusDraft,
 	}
 
-	// insert data into db,  result contains the newly generated Object ID for the new document
-	result, err := blogdb.InsertOne(mongoCtx, data)
+	// created contains the newly generated Object ID for the new blog
+	created, err := store.Create(ctx, data)
 	if err != nil {
-		// return internal gRPC error to be handled later
-		return nil, status.Errorf(codes.Internal, fmt.Sprintf("Internal error: %v", err))
+		// return the gRPC error to be handled later, Unavailable when the database can't be reached
+		return nil, storeError(err, "Could not create the blog")
 	}
 
-	// adding id to the blog, , first cast the "generic type" (go doesn't have real generics yet) to an Object ID.
-	oid := result.InsertedID.(primitive.ObjectID)
-
-	// convert the id to its string counterpart
-	blog.Id = oid.Hex()
-
-	return &blogpb.CreateBlogRes{Blog: blog}, nil
+	return &blogpb.CreateBlogRes{Blog: created.toProto()}, nil
 }
 
-// The MongoDB FindOne() methods takes in a context and a filter, which is a BSON document for which to filter by its keys
+// ReadBlog returns the latest state of a blog, or an older one when a revision or a point in time is asked for
 func (s *BlogServiceServer) ReadBlog(ctx context.Context, req *blogpb.ReadBlogReq) (*blogpb.ReadBlogRes, error) {
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	blogId := req.GetId()
 
 	// converting string blogId from pb to mongoDB ObjectId
@@ -65,69 +106,196 @@ func (s *BlogServiceServer) ReadBlog(ctx context.Context, req *blogpb.ReadBlogRe
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
 	}
+	if req.GetRevision() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Revision can't be negative: %d", req.GetRevision()))
+	}
+	if req.GetRevision() != 0 && req.GetAsOf() != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Only one of revision and as_of can be set")
+	}
 
-	blogFromDB := blogdb.FindOne(ctx, bson.M{"_id": oid})
-
-	// creating an empty BlogItem, then write our decoded blogFromDB to emptyBlog
-	data := BlogItem{}
-
-	err = blogFromDB.Decode(&data)
+	data, err := store.Get(ctx, oid)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("Could not find blog with Object Id %s:%v", blogId, err))
+		return nil, storeError(err, fmt.Sprintf("Could not find blog with Object Id %s", blogId))
 	}
 
-	// cast it to ReadBlogRes type
-	response := &blogpb.ReadBlogRes{
-		Blog: &blogpb.Blog{
-			Id:       oid.Hex(),
-			AuthorId: data.AuthorID,
-			Title:    data.Title,
-			Content:  data.Content,
-		},
+	var rev *RevisionItem
+	switch {
+	case req.GetRevision() != 0:
+		rev, err = store.GetRevision(ctx, oid, req.GetRevision())
+	case req.GetAsOf() != nil:
+		if err := req.GetAsOf().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Invalid as_of: %v", err))
+		}
+		rev, err = store.GetRevisionAt(ctx, oid, req.GetAsOf().AsTime())
+	default:
+		return &blogpb.ReadBlogRes{Blog: data.toProto(), Revision: data.Revision}, nil
+	}
+	if err != nil {
+		return nil, storeError(err, fmt.Sprintf("Could not find the requested revision of blog %s", blogId))
 	}
 
-	return response, nil
+	return &blogpb.ReadBlogRes{Blog: rev.toProto().GetBlog(), Revision: rev.Revision}, nil
 }
 
 func (s *BlogServiceServer) UpdateBlog(ctx context.Context, req *blogpb.UpdateBlogReq) (*blogpb.UpdateBlogRes, error) {
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	blog := req.GetBlog()
 
 	id := blog.GetId() // as string
 
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert the supplied blog id to a MongoDB ObjectId: %v", err))
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert the supplied blog id to a MongoDB ObjectId: %v", err))
 	}
 
-	// convert the data to be updated into an unordered Bson document
-	update := bson.M{
-		"author_id": blog.GetAuthorId(),
-		"title":     blog.GetTitle(),
-		"content":   blog.GetContent(),
+	fields, err := updateFields(req.GetUpdateMask())
+	if err != nil {
+		return nil, err
 	}
 
-	// convert oid also to an unordered bson data, it'll be same as previous
-	filter := bson.M{"_id": oid}
+	author := blog.GetAuthorId()
+	editor, err := editorOf(ctx, req.GetEditorId())
+	if err != nil {
+		return nil, err
+	}
 
-	// result is the BSON encoded result
-	// To return the updated document instead of original we have to add options.
-	result := blogdb.FindOneAndUpdate(ctx, filter, bson.M{"$set": update}, options.FindOneAndUpdate().SetReturnDocument(1))
+	// the ownership checks and the default editor read the stored blog, once
+	expectedVersion := req.GetExpectedVersion()
+	authenticated := principalFromContext(ctx) != nil
+	if authenticated || (editor == "" && !containsField(fields, "author_id")) {
+		current, err := store.Get(ctx, oid)
+		if err != nil {
+			return nil, storeError(err, fmt.Sprintf("Could not find blog with Supplied ID %s", id))
+		}
+		// what was checked is this version, a write landing in between fails the update instead of slipping through
+		if expectedVersion == 0 {
+			expectedVersion = current.Revision
+		}
+
+		// authenticated callers change their own blogs, giving one to another author takes a role changing every blog
+		if authenticated {
+			if err := s.checkOwner(ctx, current.AuthorID); err != nil {
+				return nil, err
+			}
+			if containsField(fields, "author_id") {
+				if author == "" {
+					author = current.AuthorID
+				}
+				if err := s.checkOwner(ctx, author); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		// the editor defaults to the author, the stored one when the update leaves it untouched
+		if editor == "" && !containsField(fields, "author_id") {
+			editor = current.AuthorID
+		}
+	}
+	if editor == "" {
+		editor = author
+	}
 
-	// decode the result
-	data := BlogItem{}
-	err = result.Decode(&data)
+	data, err := store.Update(ctx, &BlogItem{
+		ID:        oid,
+		AuthorID:  author,
+		Title:     blog.GetTitle(),
+		Content:   blog.GetContent(),
+		UpdatedBy: editor,
+		UpdatedAt: now(),
+	}, fields, expectedVersion)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("Could not find blog with Supplied ID %s: %v", id, err))
+		return nil, storeError(err, fmt.Sprintf("Could not update blog with Supplied ID %s", id))
 	}
 
-	response := &blogpb.UpdateBlogRes{
-		Blog: &blogpb.Blog{Id: data.ID.Hex(), AuthorId: data.AuthorID, Title: data.Title, Content: data.Content},
+	return &blogpb.UpdateBlogRes{Blog: data.toProto(), Revision: data.Revision}, nil
+}
+
+// updateFields validates the update mask of an UpdateBlog call, an empty mask updates every field
+func updateFields(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	if len(mask.GetPaths()) == 0 {
+		return blogFields, nil
+	}
+
+	fields := make([]string, 0, len(mask.GetPaths()))
+	for _, path := range mask.GetPaths() {
+		if !containsField(blogFields, path) {
+			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Unknown update_mask path %q, expected one of %v", path, blogFields))
+		}
+		fields = append(fields, path)
 	}
-	return response, nil
+	return fields, nil
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// RestoreBlogRevision brings back the fields of an older revision, as a new revision on top of the history
+func (s *BlogServiceServer) RestoreBlogRevision(ctx context.Context, req *blogpb.RestoreBlogRevisionReq) (*blogpb.RestoreBlogRevisionRes, error) {
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := req.GetId()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
+	}
+
+	rev, err := store.GetRevision(ctx, oid, req.GetRevision())
+	if err != nil {
+		return nil, storeError(err, fmt.Sprintf("Could not find revision %d of blog %s", req.GetRevision(), id))
+	}
+
+	// restoring a revision written by another author gives the blog back to them, the caller must be allowed both
+	if err := s.checkBlogOwner(ctx, oid, store.Get); err != nil {
+		return nil, err
+	}
+	if err := s.checkOwner(ctx, rev.AuthorID); err != nil {
+		return nil, err
+	}
+
+	editor, err := editorOf(ctx, req.GetEditorId())
+	if err != nil {
+		return nil, err
+	}
+	if editor == "" {
+		editor = rev.AuthorID
+	}
+
+	data, err := store.Update(ctx, &BlogItem{
+		ID:        oid,
+		AuthorID:  rev.AuthorID,
+		Title:     rev.Title,
+		Content:   rev.Content,
+		UpdatedBy: editor,
+		UpdatedAt: now(),
+	}, blogFields, req.GetExpectedVersion())
+	if err != nil {
+		return nil, storeError(err, fmt.Sprintf("Could not restore blog %s", id))
+	}
+
+	return &blogpb.RestoreBlogRevisionRes{Blog: data.toProto(), Revision: data.Revision}, nil
 }
 
-// For DeleteBlog we’ll use the mongodb DeleteOne() method, which takes in an Object ID of the document to remove.
 func (s *BlogServiceServer) DeleteBlog(ctx context.Context, req *blogpb.DeleteBlogReq) (*blogpb.DeleteBlogRes, error) {
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	idAsString := req.GetId()
 
 	oid, err := primitive.ObjectIDFromHex(idAsString)
@@ -135,120 +303,562 @@ func (s *BlogServiceServer) DeleteBlog(ctx context.Context, req *blogpb.DeleteBl
 		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
 	}
 
-	// we're returning boolean not BlogItem
-	_, err = blogdb.DeleteOne(ctx, bson.M{"_id": oid})
+	if err := s.checkBlogOwner(ctx, oid, store.Get); err != nil {
+		return nil, err
+	}
+
+	// the blog only moves to the trash, UndeleteBlog can bring it back until it gets purged
+	err = store.Delete(ctx, oid, req.GetExpectedVersion(), now())
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, fmt.Sprintf("Couldn't find/delete blog with id %s : %v", idAsString, err))
+		return nil, storeError(err, fmt.Sprintf("Couldn't find/delete blog with id %s", idAsString))
 	}
 
 	return &blogpb.DeleteBlogRes{Success: true}, nil
 }
 
-// func (s *BlogServiceServer) ListBlogs(req *blogpb.ListBlogsReq, stream blogpb.BlogService_ListBlogsServer) error {
-// 	// Initiate a BlogItem type to write decoded data to
-// 	data := &BlogItem{}
-// 	// collection.Find returns a cursor for our (empty) query
-// 	cursor, err := blogdb.Find(context.Background(), bson.M{})
-// 	if err != nil {
-// 		return status.Errorf(codes.Internal, fmt.Sprintf("Unknown internal error: %v", err))
-// 	}
-// 	// An expression with defer will be called at the end of the function
-// 	defer cursor.Close(context.Background())
-// 	// cursor.Next() returns a boolean, if false there are no more items and loop will break
-// 	for cursor.Next(context.Background()) {
-// 		// Decode the data at the current pointer and write it to data
-// 		err := cursor.Decode(data)
-// 		// check error
-// 		if err != nil {
-// 			return status.Errorf(codes.Unavailable, fmt.Sprintf("Could not decode data: %v", err))
-// 		}
-// 		// If no error is found send blog over stream
-// 		stream.Send(&blogpb.ListBlogsRes{
-// 			Blog: &blogpb.Blog{
-// 				Id:       data.ID.Hex(),
-// 				AuthorId: data.AuthorID,
-// 				Content:  data.Content,
-// 				Title:    data.Title,
-// 			},
-// 		})
-// 	}
-// 	// Check if the cursor has any errors
-// 	if err := cursor.Err(); err != nil {
-// 		return status.Errorf(codes.Internal, fmt.Sprintf("Unkown cursor error: %v", err))
-// 	}
-// 	return nil
-// }
-
-type BlogItem struct {
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	AuthorID string             `bson:"author_id"`
-	Content  string             `bson:"content"`
-	Title    string             `bson:"title"`
+func (s *BlogServiceServer) UndeleteBlog(ctx context.Context, req *blogpb.UndeleteBlogReq) (*blogpb.UndeleteBlogRes, error) {
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := req.GetId()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
+	}
+
+	if err := s.checkBlogOwner(ctx, oid, store.GetDeleted); err != nil {
+		return nil, err
+	}
+
+	data, err := store.Undelete(ctx, oid)
+	if err != nil {
+		return nil, storeError(err, fmt.Sprintf("Could not find blog %s in the trash", id))
+	}
+
+	return &blogpb.UndeleteBlogRes{Blog: data.toProto()}, nil
+}
+
+// PurgeBlog deletes a blog for good, only blogs already in the trash can be purged
+func (s *BlogServiceServer) PurgeBlog(ctx context.Context, req *blogpb.PurgeBlogReq) (*blogpb.PurgeBlogRes, error) {
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := req.GetId()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
+	}
+
+	if err := s.checkBlogOwner(ctx, oid, store.GetDeleted); err != nil {
+		return nil, err
+	}
+
+	if err := store.Purge(ctx, oid); err != nil {
+		return nil, storeError(err, fmt.Sprintf("Could not find blog %s in the trash", id))
+	}
+
+	return &blogpb.PurgeBlogRes{Success: true}, nil
+}
+
+// ListBlog streams the blogs matching the request filters one by one, in the requested order.
+// Every response carries a cursor, so a client whose stream dropped can resume right after the last blog it got.
+func (s *BlogServiceServer) ListBlog(req *blogpb.ListBlogReq, stream blogpb.BlogService_ListBlogServer) error {
+	// the stream context is cancelled as soon as the client goes away, so the store stops reading too
+	ctx := stream.Context()
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	if req.GetPageSize() < 0 {
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf("Page size can't be negative: %d", req.GetPageSize()))
+	}
+
+	statusFilter := ""
+	if req.GetStatus() != blogpb.Blog_UNSPECIFIED {
+		var ok bool
+		if statusFilter, ok = statusFromProto[req.GetStatus()]; !ok {
+			return status.Errorf(codes.InvalidArgument, fmt.Sprintf("Unknown status: %v", req.GetStatus()))
+		}
+	}
+
+	after, err := decodeListCursor(req)
+	if err != nil {
+		return err
+	}
+
+	query := ListQuery{
+		AuthorID:    req.GetAuthorId(),
+		TitlePrefix: req.GetTitlePrefix(),
+		SortBy:      req.GetSortBy(),
+		Desc:        req.GetSortDirection() == blogpb.ListBlogReq_DESC,
+		Limit:       int64(req.GetPageSize()),
+		After:       after,
+		Status:      statusFilter,
+	}
+
+	err = store.List(ctx, query, func(data *BlogItem) error {
+		return stream.Send(&blogpb.ListBlogRes{
+			Blog:          data.toProto(),
+			NextPageToken: encodeListCursor(req, data),
+		})
+	})
+	if err != nil {
+		return storeError(err, "Could not list blogs")
+	}
+	return nil
+}
+
+// ListDeletedBlogs streams the content of the trash, authenticated callers only see their own blogs unless they can change every blog
+func (s *BlogServiceServer) ListDeletedBlogs(req *blogpb.ListDeletedBlogsReq, stream blogpb.BlogService_ListDeletedBlogsServer) error {
+	store, err := s.storeOf(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	query := ListQuery{
+		AuthorID: req.GetAuthorId(),
+		Deleted:  true,
+	}
+	if p := principalFromContext(stream.Context()); p != nil && query.AuthorID == "" && !s.policy.allBlogs(p) {
+		query.AuthorID = p.Subject
+	}
+	if err := s.checkOwner(stream.Context(), query.AuthorID); err != nil {
+		return err
+	}
+
+	err = store.List(stream.Context(), query, func(data *BlogItem) error {
+		return stream.Send(&blogpb.ListDeletedBlogsRes{
+			Blog:      data.toProto(),
+			DeletedAt: timestamppb.New(*data.DeletedAt),
+		})
+	})
+	if err != nil {
+		return storeError(err, "Could not list deleted blogs")
+	}
+	return nil
+}
+
+// ListBlogRevisions streams the whole history of a blog, oldest revision first
+func (s *BlogServiceServer) ListBlogRevisions(req *blogpb.ListBlogRevisionsReq, stream blogpb.BlogService_ListBlogRevisionsServer) error {
+	ctx := stream.Context()
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return err
+	}
+	id := req.GetId()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
+	}
+
+	// an unknown blog is an error, not an empty history
+	if _, err := store.Get(ctx, oid); err != nil {
+		return storeError(err, fmt.Sprintf("Could not find blog with Object Id %s", id))
+	}
+
+	err = store.ListRevisions(ctx, oid, func(rev *RevisionItem) error {
+		return stream.Send(&blogpb.ListBlogRevisionsRes{Revision: rev.toProto()})
+	})
+	if err != nil {
+		return storeError(err, fmt.Sprintf("Could not list revisions of blog %s", id))
+	}
+	return nil
+}
+
+// storeError converts an error returned by the BlogStore (or by a stream send) into a gRPC status error
+func storeError(err error, msg string) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var mismatch *VersionMismatchError
+	var wrongStatus *StatusError
+	switch {
+	case errors.As(err, &mismatch):
+		return versionMismatchStatus(mismatch)
+	case errors.As(err, &wrongStatus):
+		return status.Errorf(codes.FailedPrecondition, fmt.Sprintf("%s: %v", msg, err))
+	case errors.Is(err, ErrBlogNotFound), errors.Is(err, ErrRevisionNotFound):
+		return status.Errorf(codes.NotFound, fmt.Sprintf("%s: %v", msg, err))
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case isTransientMongoError(err):
+		// the database is unreachable or changing its primary, the client may retry
+		return status.Errorf(codes.Unavailable, fmt.Sprintf("%s: %v", msg, err))
+	default:
+		return status.Errorf(codes.Internal, fmt.Sprintf("%s: %v", msg, err))
+	}
+}
+
+// versionMismatchStatus is the ABORTED status of a stale write, the current version is in its details
+// so the client can re-read the blog and retry without guessing
+func versionMismatchStatus(mismatch *VersionMismatchError) error {
+	st := status.New(codes.Aborted, mismatch.Error())
+	detailed, err := st.WithDetails(&blogpb.VersionMismatch{
+		Id:              mismatch.ID.Hex(),
+		ExpectedVersion: mismatch.Expected,
+		CurrentVersion:  mismatch.Current,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// now is the time recorded on changes, Mongo only keeps milliseconds so every store is truncated the same way
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
 }
 
 func main() {
-	// configure log package to produce line number if in case og log.Fatalf(), (log.LstdFLags = log.Ldate | log.Ltime)
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	fmt.Println("Starting server on port : 4000...")
+	cfg, printConfig, err := loadConfig(os.Args[0], os.Args[1:], os.LookupEnv)
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Could not load the configuration: %v", err)
+	}
+	if printConfig {
+		out, err := cfg.printable()
+		if err != nil {
+			log.Fatalf("Could not print the configuration: %v", err)
+		}
+		os.Stdout.Write(out)
+		return
+	}
+
+	// everything is logged as JSON lines from here on, what other packages write with the log package included
+	logger, err := newLogger(cfg.Log)
+	if err != nil {
+		log.Fatalf("Could not set up logging: %v", err)
+	}
+	defer logger.Sync()
+	zap.ReplaceGlobals(logger)
+	zap.RedirectStdLog(logger)
+	logger.Info("Starting server", zap.String("addr", cfg.ListenAddr))
+
+	// tracing is set up first, so the spans of the MongoDB commands of the startup are exported too
+	var tracerProvider *sdktrace.TracerProvider
+	if cfg.Tracing.enabled() {
+		tracerProvider, err = setupTracing(cfg.Tracing)
+		if err != nil {
+			logger.Fatal("Failed to set up tracing", zap.Error(err))
+		}
+		logger.Info("Exporting traces", zap.String("exporter", cfg.Tracing.Exporter), zap.Float64("sample_ratio", cfg.Tracing.SampleRatio))
+	}
 
 	// start our listner
-	listener, err := net.Listen("tcp", ":4000")
+	listener, err := net.Listen("tcp", cfg.ListenAddr)
 	if err != nil {
-		log.Fatalf("Failed to listen to the server port: 4000 , %v", err)
+		logger.Fatal("Failed to listen", zap.String("addr", cfg.ListenAddr), zap.Error(err))
+	}
+
+	// pick the storage backend before anything is served, it keeps the API keys too.
+	// With tenancy every tenant gets a store of its own from the backend, the handlers can't reach the others.
+	var stores *tenantStores
+	var keys APIKeyStore
+	var mongoDB *mongoStore
+	switch cfg.Store {
+	case "mongo":
+		// INITIALIZE MONGODB CLIENT
+		mongoCtx = context.Background() // non nil empty context
+
+		// Connect only checks the options, the driver reaches the servers in the background: the server
+		// starts without MongoDB, and connects to it once serving (see connectMongo below).
+		// The monitors feed the command latencies and the pool stats to the metrics, and the commands to the traces.
+		client, err := mongo.Connect(mongoCtx, options.Client().ApplyURI(cfg.Mongo.URI).
+			SetMonitor(traceMongoCommands(newMongoCommandMonitor())).
+			SetPoolMonitor(newMongoPoolMonitor()))
+		if err != nil {
+			logger.Fatal("Failed to connect to mongo", zap.Error(err))
+		}
+		db = client
+
+		database := db.Database(cfg.Mongo.Database)
+		mongoDB = newMongoStore(database.Collection(cfg.Mongo.Collection), database.Collection(cfg.Mongo.RevisionsCollection), database.Collection(cfg.Mongo.APIKeysCollection))
+		stores, keys = singleTenant(mongoDB), mongoDB
+		if cfg.Tenancy.enabled() {
+			stores = newMongoTenants(mongoDB, cfg.Tenancy.Mode)
+		}
+	case "bolt":
+		boltDB, err := newBoltStore(cfg.BoltPath)
+		if err != nil {
+			logger.Fatal("Failed to open the bolt store", zap.Error(err))
+		}
+		defer boltDB.Close()
+		logger.Info("Using the bolt store", zap.String("path", cfg.BoltPath))
+		stores, keys = singleTenant(boltDB), boltDB
+		if cfg.Tenancy.enabled() {
+			stores = newBoltTenants(cfg.BoltPath)
+			defer stores.Close()
+		}
+	case "memory":
+		logger.Info("Using the in-memory store, blogs won't survive a restart")
+		memDB, err := newMemoryStore()
+		if err != nil {
+			logger.Fatal("Failed to create the in-memory store", zap.Error(err))
+		}
+		stores, keys = singleTenant(memDB), memDB
+		if cfg.Tenancy.enabled() {
+			stores = newTenantStores(func(context.Context, string) (BlogStore, error) {
+				return newMemoryStore()
+			}, func(context.Context) ([]string, error) {
+				return nil, nil
+			})
+		}
 	}
 
 	// creating a new grpcServer with blank opts
 	opts := []grpc.ServerOption{}
+
+	// TLS, and mTLS when a client CA bundle is configured, certificates are reloaded when they change on disk
+	var certs *certReloader
+	if cfg.TLS.enabled() {
+		certs, err = newCertReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ClientCAFile)
+		if err != nil {
+			logger.Fatal("Failed to load the TLS files", zap.Error(err))
+		}
+		opts = append(opts, grpc.Creds(gatewayCredentials{credentials.NewTLS(certs.config("h2"))}))
+		logger.Info("Serving with TLS", zap.Bool("client_certificates", cfg.TLS.ClientCAFile != ""))
+	}
+
+	// interceptors run in order, before the handlers. The metrics come first, to count the calls the others reject,
+	// after the tracking of the handlers the shutdown waits for.
+	handlers := newHandlerTracker()
+	unary := []grpc.UnaryServerInterceptor{handlers.unaryInterceptor, metricsUnaryInterceptor}
+	stream := []grpc.StreamServerInterceptor{handlers.streamInterceptor, metricsStreamInterceptor}
+	if tracerProvider != nil {
+		unary = append(unary, tracingUnaryInterceptor)
+		stream = append(stream, tracingStreamInterceptor)
+	}
+	// the calls are logged within their span, so their log lines carry its trace id
+	calls := newCallLogger(logger, cfg.Log.RedactContent)
+	unary = append(unary, calls.unaryInterceptor)
+	stream = append(stream, calls.streamInterceptor)
+
+	// clients are told apart by their credentials, so they are rate limited once authenticated (see below).
+	// Their failed authentications can only be told apart by address, they are limited before authenticating.
+	var limiter *rateLimiter
+	limitAuthFailures := cfg.Auth.JWKSFile != "" && cfg.RateLimit.Unauthenticated.Rate > 0
+	if cfg.RateLimit.enabled() || limitAuthFailures {
+		limiter = newRateLimiter(cfg.RateLimit)
+	}
+
+	if cfg.Auth.JWKSFile != "" {
+		auth, err := newJWTAuthenticator(cfg.Auth)
+		if err != nil {
+			logger.Fatal("Failed to set up authentication", zap.Error(err))
+		}
+		if limitAuthFailures {
+			unary = append(unary, limiter.authFailuresUnaryInterceptor)
+			stream = append(stream, limiter.authFailuresStreamInterceptor)
+			logger.Info("Rate limiting failed authentications", zap.Float64("rate", cfg.RateLimit.Unauthenticated.Rate),
+				zap.Int("burst", cfg.RateLimit.Unauthenticated.Burst))
+		}
+		// the calls with an API key are authenticated first, the others need a bearer token
+		apiKeys := &apiKeyAuthenticator{keys: keys}
+		unary = append(unary, apiKeys.unaryInterceptor, auth.unaryInterceptor)
+		stream = append(stream, apiKeys.streamInterceptor, auth.streamInterceptor)
+		logger.Info("Authenticating calls with JWT bearer tokens", zap.String("jwks_file", cfg.Auth.JWKSFile))
+	} else {
+		logger.Warn("Authentication is disabled, anyone can call every method")
+	}
+
+	if cfg.RateLimit.enabled() {
+		unary = append(unary, limiter.unaryInterceptor)
+		stream = append(stream, limiter.streamInterceptor)
+		logger.Info("Rate limiting clients", zap.Float64("rate", cfg.RateLimit.Default.Rate), zap.Int("burst", cfg.RateLimit.Default.Burst),
+			zap.Int("methods", len(cfg.RateLimit.Methods)))
+	}
+
+	// the policy checks the roles of the principals, so its interceptors come after the authentication
+	var policy *Policy
+	if cfg.Auth.PolicyFile != "" {
+		policy, err = loadPolicy(cfg.Auth.PolicyFile)
+		if err != nil {
+			logger.Fatal("Failed to load the access control policy", zap.Error(err))
+		}
+		unary = append(unary, policy.unaryInterceptor)
+		stream = append(stream, policy.streamInterceptor)
+		logger.Info("Checking calls against the access control policy", zap.String("policy_file", cfg.Auth.PolicyFile))
+	}
+
+	// the tenant comes from the credentials when there are some, so it is resolved once the call is authenticated
+	if cfg.Tenancy.enabled() {
+		tenants := &tenantResolver{defaultTenant: cfg.Tenancy.DefaultTenant}
+		unary = append(unary, tenants.unaryInterceptor)
+		stream = append(stream, tenants.streamInterceptor)
+		logger.Info("Isolating tenants", zap.String("mode", cfg.Tenancy.Mode), zap.String("default_tenant", cfg.Tenancy.DefaultTenant))
+	}
+
+	opts = append(opts, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
 	grpcServer := grpc.NewServer(opts...)
-	srv := &BlogServiceServer{}
+	srv := NewBlogServiceServer(stores, policy)
 
 	// registering the microservice with grpc server
 	blogpb.RegisterBlogServiceServer(grpcServer, srv)
+	blogpb.RegisterApiKeyServiceServer(grpcServer, NewAPIKeyServiceServer(keys))
+
+	// standard health checking for the orchestrator, and reflection so tools like grpcurl can discover the services
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+	// MongoDB isn't reached yet, the server is only ready once connected
+	setServing(healthServer, db == nil)
+
+	// background jobs stop when the server does, which waits for them before closing the store
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobs sync.WaitGroup
+	runJob := func(job func()) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			job()
+		}()
+	}
 
-	// INITIALIZE MONGODB CLIENT
-	fmt.Println("Connecting to MongoDB...")
-	mongoCtx = context.Background() // non nil empty context
-
-	client, err := mongo.Connect(mongoCtx, options.Client().ApplyURI("mongodb://localhost:27017"))
-	if err != nil {
-		log.Fatalf("Failed to connect to mongo: ", err)
+	// the jobs using the store wait for it to be ready, the embedded ones are right away
+	storeReady := make(chan struct{})
+	if db == nil {
+		close(storeReady)
+	}
+	runStoreJob := func(job func()) {
+		runJob(func() {
+			select {
+			case <-storeReady:
+				job()
+			case <-jobsCtx.Done():
+			}
+		})
 	}
 
-	// check for successful conection by pinging to MongoDB server
-	err = db.Ping(mongoCtx, nil)
-	if err != nil {
-		log.Fatalf("Could not connect to MongoDB: %v", err)
+	if cfg.TrashRetention > 0 {
+		runStoreJob(func() { runPurger(jobsCtx, stores, time.Duration(cfg.TrashRetention)) })
+	}
+	runStoreJob(func() { runScheduler(jobsCtx, stores, time.Duration(cfg.ScheduleInterval)) })
+	if limiter != nil {
+		runJob(func() { limiter.run(jobsCtx, rateLimitPruneInterval) })
+	}
+	if certs != nil {
+		runJob(func() { certs.run(jobsCtx, time.Duration(cfg.TLS.ReloadInterval)) })
 	}
-	log.Printf("Connected to MongoDB.!")
 
-	// binding our connection to our Global variable so to be used in other method
-	blogdb = db.Database("mydb").Collection("blog")
+	// the embedded stores can't be lost, only MongoDB needs connecting to, with its indexes, and then watching.
+	// The health service answers all along, NOT_SERVING until connected and whenever MongoDB is lost.
+	if db != nil {
+		runJob(func() {
+			logger.Info("Connecting to MongoDB", zap.String("uri", redactPassword(cfg.Mongo.URI)))
+			err := connectMongo(jobsCtx, time.Duration(cfg.Mongo.ConnectTimeout), func(ctx context.Context) error {
+				if err := db.Ping(ctx, nil); err != nil {
+					return err
+				}
+				return mongoDB.ensureIndexes(ctx)
+			})
+			if err != nil {
+				// shutting down before MongoDB was reached
+				return
+			}
+			logger.Info("Connected to MongoDB, now SERVING")
+			setServing(healthServer, true)
+			close(storeReady)
+
+			runHealthCheck(jobsCtx, healthServer, time.Duration(cfg.HealthInterval), func(ctx context.Context) error {
+				return db.Ping(ctx, nil)
+			})
+		})
+	}
 
 	// STARTING SERVER IN CHILD GOROUTE
 	go func() {
 		err := grpcServer.Serve(listener)
 		if err != nil {
-			log.Fatalf("Failed to serve: %v", err)
+			logger.Fatal("Failed to serve", zap.Error(err))
 		}
 	}()
-	fmt.Println("Server  started successfully on port:4000")
+	logger.Info("Server started", zap.String("addr", cfg.ListenAddr))
 
-	// server SHUTDOWN hook to stop server properly
-	shutdownSignalChannel := make(chan os.Signal)
-	signal.Notify(shutdownSignalChannel, os.Kill)
-	signal.Notify(shutdownSignalChannel, os.Interrupt)
+	// the REST/JSON gateway proxies to the gRPC server above
+	var httpServer *http.Server
+	if cfg.HTTPAddr != "" {
+		gateway, pipe, err := newGateway(jobsCtx)
+		if err != nil {
+			logger.Fatal("Failed to create the REST gateway", zap.Error(err))
+		}
+		go grpcServer.Serve(pipe)
+
+		// the gateway is as secure as the gRPC server, with the same certificate and client verification
+		httpServer = &http.Server{Addr: cfg.HTTPAddr, Handler: newHTTPHandler(gateway)}
+		go func() {
+			var err error
+			if certs != nil {
+				httpServer.TLSConfig = certs.config("h2", "http/1.1")
+				err = httpServer.ListenAndServeTLS("", "")
+			} else {
+				err = httpServer.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				logger.Fatal("Failed to serve the REST gateway", zap.Error(err))
+			}
+		}()
+		logger.Info("REST gateway started", zap.String("addr", cfg.HTTPAddr), zap.String("explorer", explorerPath))
+	}
 
-	_ = <-shutdownSignalChannel
+	// the admin server serves the metrics
+	var adminServer *http.Server
+	if cfg.AdminAddr != "" {
+		adminServer = &http.Server{Addr: cfg.AdminAddr, Handler: newAdminHandler()}
+		go func() {
+			err := adminServer.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				logger.Fatal("Failed to serve the admin server", zap.Error(err))
+			}
+		}()
+		logger.Info("Admin server started", zap.String("addr", cfg.AdminAddr), zap.String("metrics", metricsPath))
+	}
 
-	// after recieveing shutdownSignal
-	fmt.Println("\n Stopping the server...")
-	grpcServer.Stop()
-	err = listener.Close()
-	if err != nil {
-		log.Println("Listener not close properly")
+	// server SHUTDOWN hook to stop server properly: SIGTERM is how orchestrators stop it, SIGINT is Ctrl+C.
+	// SIGKILL can't be caught, the server has to be done before it comes.
+	shutdownSignalChannel := make(chan os.Signal, 1)
+	signal.Notify(shutdownSignalChannel, syscall.SIGTERM, os.Interrupt)
+
+	sig := <-shutdownSignalChannel
+
+	// after recieveing shutdownSignal, probes see NOT_SERVING right away so no new calls are sent while the running ones finish,
+	// the ones sent before the probes noticed are still taken during the drain delay
+	logger.Info("Stopping the server", zap.Stringer("signal", sig),
+		zap.Duration("drain_delay", time.Duration(cfg.DrainDelay)), zap.Duration("timeout", time.Duration(cfg.ShutdownTimeout)))
+	healthServer.Shutdown()
+	awaitDrainDelay(time.Duration(cfg.DrainDelay), shutdownSignalChannel)
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout))
+	drainServers(drainCtx, grpcServer, httpServer, handlers, shutdownSignalChannel)
+	cancelDrain()
+
+	stopJobs()
+	jobs.Wait()
+	if adminServer != nil {
+		adminServer.Close()
+	}
+
+	// the spans of the last calls are exported once they are over
+	if tracerProvider != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+		if err := tracerProvider.Shutdown(ctx); err != nil {
+			logger.Error("Could not export the last spans", zap.Error(err))
+		}
+		cancel()
 	}
 
-	fmt.Println("Closing MongoDB connection")
-	db.Disconnect(mongoCtx)
-	fmt.Println("All command executed, done.!")
+	if db != nil {
+		logger.Info("Closing MongoDB connection")
+		db.Disconnect(mongoCtx)
+	}
+	logger.Info("Server stopped")
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestUpdateFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		paths   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no mask", paths: nil, want: blogFields},
+		{name: "one field", paths: []string{"title"}, want: []string{"title"}},
+		{name: "every field", paths: []string{"content", "author_id", "title"}, want: []string{"content", "author_id", "title"}},
+		{name: "the id", paths: []string{"id"}, wantErr: true},
+		{name: "a json name", paths: []string{"authorId"}, wantErr: true},
+		{name: "an unknown path among known ones", paths: []string{"title", "tags"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		var mask *fieldmaskpb.FieldMask
+		if tt.paths != nil {
+			mask = &fieldmaskpb.FieldMask{Paths: tt.paths}
+		}
+		got, err := updateFields(mask)
+		if tt.wantErr {
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("%s: updateFields(%v) returned %v, want InvalidArgument", tt.name, tt.paths, err)
+			}
+			continue
+		}
+		if err != nil || !equalStrings(got, tt.want) {
+			t.Errorf("%s: updateFields(%v) returned %v, %v, want %v", tt.name, tt.paths, got, err, tt.want)
+		}
+	}
+}
+
+func TestUpdateBlogMask(t *testing.T) {
+	store, err := newMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := createBlog(t, store, "alice", "first")
+	s := NewBlogServiceServer(singleTenant(store), nil)
+
+	res, err := s.UpdateBlog(context.Background(), &blogpb.UpdateBlogReq{
+		Blog:       &blogpb.Blog{Id: created.ID.Hex(), Title: "changed"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateBlog: %v", err)
+	}
+	// the fields left out of the mask keep their value, even blank in the request
+	got := res.GetBlog()
+	if got.GetTitle() != "changed" || got.GetContent() != "content of first" || got.GetAuthorId() != "alice" {
+		t.Errorf("UpdateBlog returned %+v", got)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// metricsPath serves the metrics on the admin server
+const metricsPath = "/metrics"
+
+// the RPC metrics are named like the ones of go-grpc-prometheus, so the usual dashboards work with them
+var (
+	rpcStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_started_total",
+		Help: "RPCs started on the server.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+	rpcHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "RPCs completed on the server, by status code.",
+	}, []string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"})
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Time the server took to handle RPCs, until the last message for the streams.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"grpc_type", "grpc_service", "grpc_method"})
+)
+
+var (
+	mongoCommandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongo_command_duration_seconds",
+		Help:    "Time MongoDB commands took, by command and outcome (succeeded or failed).",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"command", "outcome"})
+	mongoPoolConnections = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mongo_pool_connections",
+		Help: "Open connections of the pool of every MongoDB server.",
+	}, []string{"address"})
+	mongoPoolConnectionsInUse = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mongo_pool_connections_in_use",
+		Help: "Connections checked out of the pool of every MongoDB server.",
+	}, []string{"address"})
+	mongoPoolCheckouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongo_pool_checkouts_total",
+		Help: "Connections asked of the pool of every MongoDB server, by outcome (succeeded or failed).",
+	}, []string{"address", "outcome"})
+	mongoPoolCleared = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongo_pool_cleared_total",
+		Help: "Times the pool of a MongoDB server was cleared, which happens when the server is lost.",
+	}, []string{"address"})
+)
+
+// newAdminHandler serves the metrics of the server, on an address of its own so they aren't exposed along with the API
+func newAdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, promhttp.Handler())
+	return mux
+}
+
+// splitMethodName returns the service and the method of the full name of a method, like "blog.BlogService" and "ReadBlog"
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
+
+// observeRPC records a call that ran from start with the outcome err
+func observeRPC(typ, fullMethod string, start time.Time, err error) {
+	service, method := splitMethodName(fullMethod)
+	rpcDuration.WithLabelValues(typ, service, method).Observe(time.Since(start).Seconds())
+	rpcHandled.WithLabelValues(typ, service, method, status.Code(err).String()).Inc()
+}
+
+// metricsUnaryInterceptor records every call. It runs first, so the calls the other interceptors reject are counted too.
+func metricsUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	service, method := splitMethodName(info.FullMethod)
+	rpcStarted.WithLabelValues("unary", service, method).Inc()
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	observeRPC("unary", info.FullMethod, start, err)
+	return resp, err
+}
+
+// metricsStreamInterceptor is metricsUnaryInterceptor for the streaming calls
+func metricsStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	typ := streamType(info)
+	service, method := splitMethodName(info.FullMethod)
+	rpcStarted.WithLabelValues(typ, service, method).Inc()
+	start := time.Now()
+	err := handler(srv, ss)
+	observeRPC(typ, info.FullMethod, start, err)
+	return err
+}
+
+// newMongoCommandMonitor records how long every command the driver sends takes
+func newMongoCommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			mongoCommandDuration.WithLabelValues(e.CommandName, "succeeded").Observe(time.Duration(e.DurationNanos).Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			mongoCommandDuration.WithLabelValues(e.CommandName, "failed").Observe(time.Duration(e.DurationNanos).Seconds())
+		},
+	}
+}
+
+// newMongoPoolMonitor follows the connections of the pools of the driver, one per server
+func newMongoPoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			switch e.Type {
+			case event.ConnectionCreated:
+				mongoPoolConnections.WithLabelValues(e.Address).Inc()
+			case event.ConnectionClosed:
+				mongoPoolConnections.WithLabelValues(e.Address).Dec()
+			case event.GetSucceeded:
+				mongoPoolConnectionsInUse.WithLabelValues(e.Address).Inc()
+				mongoPoolCheckouts.WithLabelValues(e.Address, "succeeded").Inc()
+			case event.GetFailed:
+				mongoPoolCheckouts.WithLabelValues(e.Address, "failed").Inc()
+			case event.ConnectionReturned:
+				mongoPoolConnectionsInUse.WithLabelValues(e.Address).Dec()
+			case event.PoolCleared:
+				mongoPoolCleared.WithLabelValues(e.Address).Inc()
+			}
+		},
+	}
+}
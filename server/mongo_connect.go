@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"go.uber.org/zap"
+)
+
+// the delays between the attempts to reach MongoDB at startup double from the min to the max, with jitter
+// so the servers started together don't all retry at once
+const (
+	mongoRetryMinDelay = 500 * time.Millisecond
+	mongoRetryMaxDelay = 15 * time.Second
+)
+
+// mongoAttemptTimeout bounds every attempt, the driver would otherwise wait for its 30s server selection timeout
+const mongoAttemptTimeout = 10 * time.Second
+
+// connectMongo calls connect until it succeeds, waiting longer and longer between the attempts, and returns ctx.Err()
+// when ctx is done first. MongoDB still unreachable after timeout is logged as an error, the attempts go on.
+func connectMongo(ctx context.Context, timeout time.Duration, connect func(ctx context.Context) error) error {
+	started := time.Now()
+	reported := false
+	delay := mongoRetryMinDelay
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, mongoAttemptTimeout)
+		err := connect(attemptCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		wait, next := mongoRetryWait(delay)
+		if !reported && time.Since(started) >= timeout {
+			// a server that exited would be restarted into the same wait, staying up keeps the probes answering NOT_SERVING
+			zap.L().Error("MongoDB is still unreachable, retrying while NOT_SERVING",
+				zap.Int("attempt", attempt), zap.Duration("after", time.Since(started).Round(time.Second)), zap.Error(err))
+			reported = true
+		} else {
+			zap.L().Warn("Could not connect to MongoDB, retrying", zap.Int("attempt", attempt), zap.Duration("retry_in", wait), zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		delay = next
+	}
+}
+
+// mongoRetryWait returns how long to wait before the attempt after one waiting delay, between half of it and all of it,
+// and the delay of the attempt after that
+func mongoRetryWait(delay time.Duration) (wait, next time.Duration) {
+	wait = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+	if next = delay * 2; next > mongoRetryMaxDelay {
+		next = mongoRetryMaxDelay
+	}
+	return wait, next
+}
+
+// transientMongoCodes are the server error codes the driver retries reads on:
+// the server is unreachable, shutting down, or no longer the primary
+var transientMongoCodes = map[int]bool{
+	6:     true, // HostUnreachable
+	7:     true, // HostNotFound
+	89:    true, // NetworkTimeout
+	91:    true, // ShutdownInProgress
+	189:   true, // PrimarySteppedDown
+	9001:  true, // SocketException
+	10107: true, // NotMaster
+	11600: true, // InterruptedAtShutdown
+	11602: true, // InterruptedDueToReplStateChange
+	13435: true, // NotMasterNoSlaveOk
+	13436: true, // NotMasterOrSecondary
+}
+
+// transientMongoLabels are the labels the driver and the server put on the errors worth retrying
+var transientMongoLabels = []string{"NetworkError", "RetryableWriteError", "TransientTransactionError"}
+
+// isTransientMongoError tells whether err comes from MongoDB being unreachable or changing its primary,
+// the call failing with it may succeed when retried
+func isTransientMongoError(err error) bool {
+	if errors.Is(err, mongo.ErrClientDisconnected) {
+		return true
+	}
+
+	hasLabel := func(labels []string) bool {
+		for _, label := range labels {
+			if containsField(transientMongoLabels, label) {
+				return true
+			}
+		}
+		return false
+	}
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return hasLabel(cmdErr.Labels) || transientMongoCodes[int(cmdErr.Code)]
+	}
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) {
+		return hasLabel(writeErr.Labels) || (writeErr.WriteConcernError != nil && transientMongoCodes[writeErr.WriteConcernError.Code])
+	}
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		return hasLabel(bulkErr.Labels) || (bulkErr.WriteConcernError != nil && transientMongoCodes[bulkErr.WriteConcernError.Code])
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	// no server could be selected, none is reachable or none can take the operation
+	var selectionErr topology.ServerSelectionError
+	return errors.As(err, &selectionErr)
+}
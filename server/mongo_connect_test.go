@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+func TestIsTransientMongoError(t *testing.T) {
+	selection := topology.ServerSelectionError{Wrapped: topology.ErrServerSelectionTimeout}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"server selection", selection, true},
+		{"wrapped server selection", fmt.Errorf("could not list the blogs: %w", selection), true},
+		{"server selection in a message", errors.New("server selection error: timed out"), false},
+		{"client disconnected", mongo.ErrClientDisconnected, true},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, true},
+		{"network error label", mongo.CommandError{Code: 1, Labels: []string{"NetworkError"}}, true},
+		{"primary stepped down", mongo.CommandError{Code: 189, Name: "PrimarySteppedDown"}, true},
+		{"not master", fmt.Errorf("update: %w", mongo.CommandError{Code: 10107, Name: "NotMaster"}), true},
+		{"unauthorized", mongo.CommandError{Code: 13, Name: "Unauthorized"}, false},
+		{"retryable write", mongo.WriteException{Labels: []string{"RetryableWriteError"}}, true},
+		{"write concern during shutdown", mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 91}}, true},
+		{"duplicate key", mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}, false},
+		{"bulk write in a failed transaction", mongo.BulkWriteException{Labels: []string{"TransientTransactionError"}}, true},
+		{"bulk write concern", mongo.BulkWriteException{WriteConcernError: &mongo.WriteConcernError{Code: 64}}, false},
+		{"blog not found", ErrBlogNotFound, false},
+	}
+	for _, tt := range tests {
+		if got := isTransientMongoError(tt.err); got != tt.want {
+			t.Errorf("%s: isTransientMongoError(%v) is %v, want %v", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestMongoRetryWait(t *testing.T) {
+	tests := []struct {
+		delay time.Duration
+		next  time.Duration
+	}{
+		{mongoRetryMinDelay, 2 * mongoRetryMinDelay},
+		{time.Second, 2 * time.Second},
+		{8 * time.Second, mongoRetryMaxDelay},
+		{mongoRetryMaxDelay, mongoRetryMaxDelay},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			wait, next := mongoRetryWait(tt.delay)
+			if wait < tt.delay/2 || wait > tt.delay || next != tt.next {
+				t.Errorf("mongoRetryWait(%v) returned %v, %v, want a wait between %v and %v, and %v", tt.delay, wait, next, tt.delay/2, tt.delay, tt.next)
+				break
+			}
+		}
+	}
+}
+
+func TestConnectMongoOutlastsTimeout(t *testing.T) {
+	var attempts []time.Time
+	err := connectMongo(context.Background(), time.Nanosecond, func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("the attempt has no deadline")
+		}
+		if attempts = append(attempts, time.Now()); len(attempts) < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil || len(attempts) != 3 {
+		t.Fatalf("connectMongo returned %v after %d attempts, want success at the third one", err, len(attempts))
+	}
+	// the attempts back off, with jitter, from the min delay
+	for i, delay := range []time.Duration{mongoRetryMinDelay, 2 * mongoRetryMinDelay} {
+		if gap := attempts[i+1].Sub(attempts[i]); gap < delay/2 || gap > delay+100*time.Millisecond {
+			t.Errorf("attempt %d came %v after the previous one, want between %v and %v", i+2, gap, delay/2, delay)
+		}
+	}
+}
+
+func TestConnectMongoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := connectMongo(ctx, time.Nanosecond, func(ctx context.Context) error {
+		if attempts++; attempts == 2 {
+			cancel()
+		}
+		return errors.New("connection refused")
+	})
+	if err != context.Canceled || attempts != 2 {
+		t.Errorf("connectMongo returned %v after %d attempts, want context.Canceled after 2", err, attempts)
+	}
+}
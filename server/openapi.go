@@ -0,0 +1,41 @@
+package main
+
+import (
+	_ "embed" // for the explorer page
+	"net/http"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+)
+
+// paths of the API contract on the HTTP server of the gateway
+const (
+	openAPIPath  = "/openapi.yaml"
+	explorerPath = "/docs"
+)
+
+// explorerPage renders the OpenAPI document with Swagger UI, which lets you try every route from a browser
+//
+//go:embed static/explorer.html
+var explorerPage []byte
+
+// newHTTPHandler serves the gateway along with its OpenAPI document and explorer
+func newHTTPHandler(gateway http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", gateway)
+	mux.HandleFunc(openAPIPath, staticHandler("application/yaml", blogpb.OpenAPI))
+	mux.HandleFunc(explorerPath, staticHandler("text/html; charset=utf-8", explorerPage))
+	return mux
+}
+
+// staticHandler serves content embedded in the binary
+func staticHandler(contentType string, content []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(content)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxPurgeInterval bounds how long an expired blog can linger in the trash past its retention
+const maxPurgeInterval = time.Hour
+
+// runPurger hard-deletes the blogs that have been in the trash for longer than retention, until ctx is done
+func runPurger(ctx context.Context, stores *tenantStores, retention time.Duration) {
+	interval := retention
+	if interval > maxPurgeInterval {
+		interval = maxPurgeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// purge once right away, the server may have been down while blogs expired
+		tenants, all, err := stores.all(ctx)
+		if err != nil && ctx.Err() == nil {
+			zap.L().Error("Could not list the tenants to purge", zap.Error(err))
+		}
+		for i, store := range all {
+			purged, err := store.PurgeDeletedBefore(ctx, now().Add(-retention))
+			if err != nil && ctx.Err() == nil {
+				zap.L().Error("Could not purge the trash", tenantField(tenants[i]), zap.Error(err))
+			}
+			if purged > 0 {
+				zap.L().Info("Purged the blogs deleted long ago", zap.Int64("purged", purged), tenantField(tenants[i]), zap.Duration("retention", retention))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
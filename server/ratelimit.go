@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var (
+	rateLimitedCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "blog_rate_limited_calls_total",
+		Help: "Calls rejected by the rate limiter, by method and by what told the client apart (api_key, principal or peer), failed_auth for the addresses failing to authenticate.",
+	}, []string{"method", "client_kind"})
+	rateLimitBuckets = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "blog_rate_limit_buckets",
+		Help: "Token buckets of the clients seen lately, one per client and method.",
+	})
+)
+
+// rateLimitPruneInterval is how often the buckets of the clients that stopped calling are dropped
+const rateLimitPruneInterval = time.Minute
+
+// rateLimiter gives every client a token bucket per method: a call takes a token, and tokens come back at the rate of the method
+type rateLimiter struct {
+	fallback RateLimit
+	limits   map[string]RateLimit // by full method name
+	// authFailures is the limit of the failed authentications of an address, whatever the method
+	authFailures RateLimit
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*tokenBucket
+}
+
+type bucketKey struct {
+	method string
+	client string
+}
+
+// authFailuresMethod is the method of the buckets of the failed authentications, one per address
+const authFailuresMethod = "authentication"
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time // when tokens was last brought up to date
+}
+
+// fill brings the tokens of b up to at
+func (b *tokenBucket) fill(at time.Time) {
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+at.Sub(b.last).Seconds()*b.limit.Rate)
+	b.last = at
+}
+
+// wait is how long until b has a token, 0 when it has one
+func (b *tokenBucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+}
+
+func newRateLimiter(cfg RateLimitConfig) *rateLimiter {
+	limits := make(map[string]RateLimit, len(cfg.Methods))
+	for method, limit := range cfg.Methods {
+		limits[fullMethodName(method)] = limit
+	}
+	return &rateLimiter{fallback: cfg.Default, limits: limits, authFailures: cfg.Unauthenticated, buckets: make(map[bucketKey]*tokenBucket)}
+}
+
+// fullMethodName returns the full name of the BlogService or ApiKeyService method with this name, blank when there is none
+func fullMethodName(method string) string {
+	services := blogpb.File_proto_blog_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		if services.Get(i).Methods().ByName(protoreflect.Name(method)) != nil {
+			return "/" + string(services.Get(i).FullName()) + "/" + method
+		}
+	}
+	return ""
+}
+
+// take takes a token from the bucket of client for fullMethod.
+// When there is none left it returns false, with how long until there is one.
+func (l *rateLimiter) take(fullMethod, client string, at time.Time) (time.Duration, bool) {
+	limit, ok := l.limits[fullMethod]
+	if !ok {
+		limit = l.fallback
+	}
+	if limit.Rate <= 0 {
+		return 0, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(bucketKey{method: fullMethod, client: client}, limit, at)
+	if wait := b.wait(); wait > 0 {
+		return wait, false
+	}
+	b.tokens--
+	return 0, true
+}
+
+// bucket returns the bucket of key filled up to at, a new one is full. l.mu must be held.
+func (l *rateLimiter) bucket(key bucketKey, limit RateLimit, at time.Time) *tokenBucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{limit: limit, tokens: float64(limit.Burst), last: at}
+		l.buckets[key] = b
+		rateLimitBuckets.Set(float64(len(l.buckets)))
+	}
+	b.fill(at)
+	return b
+}
+
+// authFailureWait returns how long the address client has to wait before trying to authenticate again, 0 when it may
+func (l *rateLimiter) authFailureWait(client string, at time.Time) time.Duration {
+	if l.authFailures.Rate <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.bucket(bucketKey{method: authFailuresMethod, client: client}, l.authFailures, at).wait()
+}
+
+// failedAuth takes a token from the bucket of the failed authentications of the address client.
+// Failures coming at once take it below zero, the address then waits for all of them.
+func (l *rateLimiter) failedAuth(client string, at time.Time) {
+	if l.authFailures.Rate <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.bucket(bucketKey{method: authFailuresMethod, client: client}, l.authFailures, at).tokens--
+}
+
+// prune drops the buckets that are full again, their clients would start over with a full bucket anyway
+func (l *rateLimiter) prune(at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, b := range l.buckets {
+		if b.fill(at); b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+	rateLimitBuckets.Set(float64(len(l.buckets)))
+}
+
+// run prunes the buckets every interval until ctx is done, so clients calling once don't stay in memory
+func (l *rateLimiter) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.prune(now())
+		}
+	}
+}
+
+// clientOf returns who a call counts against, and what told them apart: their API key, their user, or their address
+func clientOf(ctx context.Context) (kind, client string) {
+	if p := principalFromContext(ctx); p != nil {
+		if p.APIKeyID != "" {
+			return "api_key", p.APIKeyID
+		}
+		// the same subject in two tenants is two users
+		return "principal", p.Tenant + "/" + p.Subject
+	}
+	return "peer", peerAddress(ctx)
+}
+
+// peerAddress returns the IP address of the client of a call.
+// The calls of the gateway come from the gateway itself, the address of the HTTP client is the last one it appended to x-forwarded-for,
+// the ones before came from the client and can't be trusted.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if _, ok := p.Addr.(gatewayAddr); ok {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			forwarded := strings.Split(values[len(values)-1], ",")
+			return strings.TrimSpace(forwarded[len(forwarded)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// limit returns ResourceExhausted, with the delay to retry after in a RetryInfo detail, when the client of the call is over its rate.
+// It runs after the authentication interceptors, so authenticated clients are limited wherever they call from.
+func (l *rateLimiter) limit(ctx context.Context, fullMethod string) error {
+	// health checks and reflection are never limited, the orchestrator must always get an answer
+	if isUnauthenticatedMethod(fullMethod) {
+		return nil
+	}
+
+	kind, client := clientOf(ctx)
+	wait, ok := l.take(fullMethod, kind+":"+client, now())
+	if ok {
+		return nil
+	}
+	rateLimitedCalls.WithLabelValues(fullMethod, kind).Inc()
+	return rateLimitedError(fmt.Sprintf("Too many calls to %s, retry in %v", fullMethod, wait.Round(time.Millisecond)), wait)
+}
+
+// rateLimitedError is the ResourceExhausted status of a rejected call, with the delay to retry after in a RetryInfo detail
+func rateLimitedError(msg string, wait time.Duration) error {
+	st := status.New(codes.ResourceExhausted, msg)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// limitAuthFailures returns ResourceExhausted, without trying the credentials of the call, when its address failed to
+// authenticate too often lately. It runs before the authentication interceptors: an unauthenticated client has nothing but
+// its address to be told apart by, and credentials must not be guessed at the speed of the server.
+func (l *rateLimiter) limitAuthFailures(ctx context.Context, fullMethod string) (string, error) {
+	client := peerAddress(ctx)
+	if isUnauthenticatedMethod(fullMethod) {
+		return client, nil
+	}
+	if wait := l.authFailureWait(client, now()); wait > 0 {
+		rateLimitedCalls.WithLabelValues(fullMethod, "failed_auth").Inc()
+		return client, rateLimitedError(fmt.Sprintf("Too many failed authentications, retry in %v", wait.Round(time.Millisecond)), wait)
+	}
+	return client, nil
+}
+
+// authFailuresUnaryInterceptor counts the calls failing to authenticate against their address, and rejects the calls of
+// the addresses over the rate
+func (l *rateLimiter) authFailuresUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	client, err := l.limitAuthFailures(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	if status.Code(err) == codes.Unauthenticated {
+		l.failedAuth(client, now())
+	}
+	return resp, err
+}
+
+// authFailuresStreamInterceptor is authFailuresUnaryInterceptor for the streaming calls
+func (l *rateLimiter) authFailuresStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	client, err := l.limitAuthFailures(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	err = handler(srv, ss)
+	if status.Code(err) == codes.Unauthenticated {
+		l.failedAuth(client, now())
+	}
+	return err
+}
+
+// unaryInterceptor rejects the calls of the clients over their rate
+func (l *rateLimiter) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := l.limit(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor is unaryInterceptor for the streaming calls, a stream takes a single token however much it sends
+func (l *rateLimiter) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.limit(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
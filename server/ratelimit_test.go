@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fromAddress returns a context of a call made from this IP address
+func fromAddress(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 50000}})
+}
+
+func TestRateLimiterTake(t *testing.T) {
+	limiter := newRateLimiter(RateLimitConfig{
+		Default: RateLimit{Rate: 2, Burst: 3},
+		Methods: map[string]RateLimit{"ReadBlog": {Rate: 0}},
+	})
+	create, read := blogServicePrefix+"CreateBlog", blogServicePrefix+"ReadBlog"
+
+	tests := []struct {
+		name     string
+		method   string
+		client   string
+		after    time.Duration // since the start of the test
+		wantOK   bool
+		wantWait time.Duration
+	}{
+		{"first of the burst", create, "alice", 0, true, 0},
+		{"second of the burst", create, "alice", 0, true, 0},
+		{"last of the burst", create, "alice", 0, true, 0},
+		{"burst spent", create, "alice", 0, false, 500 * time.Millisecond},
+		{"half a token back", create, "alice", 250 * time.Millisecond, false, 250 * time.Millisecond},
+		{"a token back", create, "alice", 500 * time.Millisecond, true, 0},
+		{"spent again", create, "alice", 500 * time.Millisecond, false, 500 * time.Millisecond},
+		{"another client", create, "bob", 500 * time.Millisecond, true, 0},
+		{"method without a limit", read, "alice", 500 * time.Millisecond, true, 0},
+		// the bucket fills up to its burst only
+		{"long after", create, "alice", time.Hour, true, 0},
+		{"long after, second", create, "alice", time.Hour, true, 0},
+		{"long after, third", create, "alice", time.Hour, true, 0},
+		{"long after, spent", create, "alice", time.Hour, false, 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		wait, ok := limiter.take(tt.method, tt.client, testTime.Add(tt.after))
+		if ok != tt.wantOK || wait != tt.wantWait {
+			t.Errorf("%s: take returned %v, %v, want %v, %v", tt.name, wait, ok, tt.wantWait, tt.wantOK)
+		}
+	}
+}
+
+func TestRateLimiterPrune(t *testing.T) {
+	limiter := newRateLimiter(RateLimitConfig{Default: RateLimit{Rate: 1, Burst: 2}})
+	method := blogServicePrefix + "CreateBlog"
+	limiter.take(method, "alice", testTime)
+	limiter.take(method, "bob", testTime)
+	limiter.take(method, "bob", testTime)
+
+	tests := []struct {
+		after time.Duration
+		want  []string
+	}{
+		{0, []string{"alice", "bob"}},
+		// alice is full again, bob has a token back only
+		{time.Second, []string{"bob"}},
+		{2 * time.Second, nil},
+	}
+	for _, tt := range tests {
+		limiter.prune(testTime.Add(tt.after))
+		for _, client := range []string{"alice", "bob"} {
+			_, kept := limiter.buckets[bucketKey{method: method, client: client}]
+			if kept != containsField(tt.want, client) {
+				t.Errorf("after %v, the bucket of %s is kept: %v, want %v", tt.after, client, kept, !kept)
+			}
+		}
+	}
+}
+
+func TestRateLimiterLimit(t *testing.T) {
+	limiter := newRateLimiter(RateLimitConfig{Default: RateLimit{Rate: 0.5, Burst: 1}})
+	method := blogServicePrefix + "CreateBlog"
+	gateway := metadata.NewIncomingContext(peer.NewContext(context.Background(), &peer.Peer{Addr: gatewayAddr{}}),
+		metadata.Pairs("x-forwarded-for", "10.0.0.9, 10.0.0.3"))
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"first call", fromAddress("10.0.0.1"), codes.OK},
+		{"second call", fromAddress("10.0.0.1"), codes.ResourceExhausted},
+		{"same user from another address", withPrincipal(fromAddress("10.0.0.2"), &Principal{Subject: "alice"}), codes.OK},
+		{"same user again", withPrincipal(fromAddress("10.0.0.1"), &Principal{Subject: "alice"}), codes.ResourceExhausted},
+		{"same user in another tenant", withPrincipal(fromAddress("10.0.0.1"), &Principal{Subject: "alice", Tenant: "acme"}), codes.OK},
+		{"API key of the user", withPrincipal(fromAddress("10.0.0.1"), &Principal{Subject: "alice", APIKeyID: "key"}), codes.OK},
+		{"through the gateway", gateway, codes.OK},
+		// the client appended the first address itself
+		{"through the gateway again", metadata.NewIncomingContext(peer.NewContext(context.Background(), &peer.Peer{Addr: gatewayAddr{}}),
+			metadata.Pairs("x-forwarded-for", "10.0.0.8, 10.0.0.3")), codes.ResourceExhausted},
+	}
+	for _, tt := range tests {
+		err := limiter.limit(tt.ctx, method)
+		if status.Code(err) != tt.want {
+			t.Errorf("%s: limit returned %v, want %v", tt.name, err, tt.want)
+			continue
+		}
+		if err == nil {
+			continue
+		}
+		// the client is told when to retry, about the 2s a token takes to come back
+		var retry *errdetails.RetryInfo
+		for _, detail := range status.Convert(err).Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok {
+				retry = info
+			}
+		}
+		if retry == nil {
+			t.Errorf("%s: %v has no RetryInfo", tt.name, err)
+		} else if delay := retry.GetRetryDelay().AsDuration(); delay <= time.Second || delay > 2*time.Second {
+			t.Errorf("%s: retry delay is %v, want about 2s", tt.name, delay)
+		}
+	}
+
+	// health checks are never limited
+	for i := 0; i < 3; i++ {
+		if err := limiter.limit(fromAddress("10.0.0.1"), "/grpc.health.v1.Health/Check"); err != nil {
+			t.Errorf("health check %d returned %v", i, err)
+		}
+	}
+}
+
+func TestLimitAuthFailures(t *testing.T) {
+	// a token comes back every ~17 minutes, none during the test
+	limiter := newRateLimiter(RateLimitConfig{Unauthenticated: RateLimit{Rate: 0.001, Burst: 2}})
+	info := &grpc.UnaryServerInfo{FullMethod: blogServicePrefix + "ReadBlog"}
+	authenticated := func(ctx context.Context, req interface{}) (interface{}, error) { return "blog", nil }
+	unauthenticated := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Errorf(codes.Unauthenticated, "bad token")
+	}
+
+	tests := []struct {
+		name    string
+		ip      string
+		handler grpc.UnaryHandler
+		want    codes.Code
+	}{
+		{"first failure", "10.0.0.1", unauthenticated, codes.Unauthenticated},
+		{"success", "10.0.0.1", authenticated, codes.OK},
+		{"second failure", "10.0.0.1", unauthenticated, codes.Unauthenticated},
+		{"over the rate", "10.0.0.1", authenticated, codes.ResourceExhausted},
+		{"another address", "10.0.0.2", unauthenticated, codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		called := false
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			called = true
+			return tt.handler(ctx, req)
+		}
+		_, err := limiter.authFailuresUnaryInterceptor(fromAddress(tt.ip), nil, info, handler)
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("%s: the call returned %v, want %v", tt.name, err, tt.want)
+		}
+		if rejected := tt.want == codes.ResourceExhausted; called == rejected {
+			t.Errorf("%s: the credentials were tried: %v, want %v", tt.name, called, !rejected)
+		}
+	}
+
+	// the health checks answer whatever the address did
+	health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	if _, err := limiter.authFailuresUnaryInterceptor(fromAddress("10.0.0.1"), nil, health, authenticated); err != nil {
+		t.Errorf("health check of a limited address returned %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// runScheduler publishes the blogs whose scheduled publication is due, every interval until ctx is done.
+// The schedule lives in the store, so publications missed while the server was down happen on the first run.
+func runScheduler(ctx context.Context, stores *tenantStores, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		tenants, all, err := stores.all(ctx)
+		if err != nil && ctx.Err() == nil {
+			zap.L().Error("Could not list the tenants to publish for", zap.Error(err))
+		}
+		for i, store := range all {
+			published, err := store.PublishDue(ctx, now())
+			if err != nil && ctx.Err() == nil {
+				zap.L().Error("Could not publish the scheduled blogs", tenantField(tenants[i]), zap.Error(err))
+			}
+			for _, data := range published {
+				zap.L().Info("Published blog as scheduled", zap.String("blog_id", data.ID.Hex()), tenantField(tenants[i]))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/analysis/lang/en"
+	"github.com/blevesearch/bleve/search/query"
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// limits on the number of results of a search
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchBlogs streams the blogs best matching a full-text query, with their matches highlighted
+func (s *BlogServiceServer) SearchBlogs(req *blogpb.SearchBlogsReq, stream blogpb.BlogService_SearchBlogsServer) error {
+	store, err := s.storeOf(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	q := parseSearchQuery(req.GetQuery())
+	if len(q.terms()) == 0 {
+		return status.Errorf(codes.InvalidArgument, "Search query must not be blank")
+	}
+	q.AuthorID = req.GetAuthorId()
+
+	switch limit := req.GetLimit(); {
+	case limit < 0:
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf("Search limit must not be negative, got %d", limit))
+	case limit == 0:
+		q.Limit = defaultSearchLimit
+	case limit > maxSearchLimit:
+		q.Limit = maxSearchLimit
+	default:
+		q.Limit = int(limit)
+	}
+
+	h := newHighlighter(q.terms())
+	err = store.Search(stream.Context(), q, func(data *BlogItem, score float64) error {
+		return stream.Send(&blogpb.SearchBlogsRes{
+			Blog:           data.toProto(),
+			Score:          score,
+			TitleSnippet:   h.mark(data.Title),
+			ContentSnippet: h.snippet(data.Content),
+		})
+	})
+	if err != nil {
+		return storeError(err, "Could not search blogs")
+	}
+	return nil
+}
+
+// SearchQuery is a parsed full-text search.
+// Like MongoDB $text search: any of the words can match, but when there are phrases every one of them must.
+type SearchQuery struct {
+	Words    []string
+	Phrases  []string
+	AuthorID string // only blogs of this author, blank for all
+	Limit    int
+}
+
+// parseSearchQuery splits a query into its bare words and its "quoted phrases"
+func parseSearchQuery(text string) SearchQuery {
+	q := SearchQuery{}
+	for i, part := range strings.Split(text, `"`) {
+		// odd parts are between quotes, an unterminated quote runs to the end of the query
+		if i%2 == 1 {
+			if phrase := strings.Join(strings.Fields(part), " "); phrase != "" {
+				q.Phrases = append(q.Phrases, phrase)
+			}
+			continue
+		}
+		q.Words = append(q.Words, strings.Fields(part)...)
+	}
+	return q
+}
+
+// terms returns everything that should be highlighted in the results
+func (q SearchQuery) terms() []string {
+	return append(append([]string{}, q.Phrases...), q.Words...)
+}
+
+// markStart and markEnd surround the matches in the snippets of search results
+const (
+	markStart = "<mark>"
+	markEnd   = "</mark>"
+
+	// snippetLength is the number of bytes of content kept around the first match
+	snippetLength = 200
+)
+
+// highlighter marks the terms of a search in the text of the results, whatever the backend ranked them with
+type highlighter struct {
+	re *regexp.Regexp
+}
+
+func newHighlighter(terms []string) *highlighter {
+	if len(terms) == 0 {
+		return &highlighter{}
+	}
+
+	// longest first, so a phrase wins over the words it contains
+	sorted := append([]string{}, terms...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	quoted := make([]string, len(sorted))
+	for i, term := range sorted {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	return &highlighter{re: regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))}
+}
+
+// mark wraps every match in text
+func (h *highlighter) mark(text string) string {
+	if h.re == nil {
+		return text
+	}
+	return h.re.ReplaceAllString(text, markStart+"${0}"+markEnd)
+}
+
+// snippet cuts the part of text around its first match and marks it
+func (h *highlighter) snippet(text string) string {
+	start, match := 0, 0
+	if h.re != nil {
+		if loc := h.re.FindStringIndex(text); loc != nil {
+			// leave a little context before the match
+			match = loc[0]
+			start = match - snippetLength/4
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	// and start it on a word
+	if start > 0 {
+		if i := strings.IndexAny(text[start:match], " \t\n"); i >= 0 {
+			start += i + 1
+		}
+	}
+	end := start + snippetLength
+	if end > len(text) {
+		end = len(text)
+	}
+
+	// never cut a multi-byte character in half
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	snippet := h.mark(text[start:end])
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// searchIndex is the embedded full-text index of the stores that don't have a database to search with.
+// It is kept in memory and only holds the blogs that aren't in the trash.
+type searchIndex struct {
+	index bleve.Index
+}
+
+// searchDocument is what gets indexed for every blog
+type searchDocument struct {
+	AuthorID string `json:"author_id"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+}
+
+func newSearchIndex() (*searchIndex, error) {
+	// english analysis for the text, so "publishing" finds "published" like with the Mongo text index
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = en.AnalyzerName
+	author := bleve.NewTextFieldMapping()
+	author.Analyzer = keyword.Name
+
+	document := bleve.NewDocumentMapping()
+	document.AddFieldMappingsAt("title", text)
+	document.AddFieldMappingsAt("content", text)
+	document.AddFieldMappingsAt("author_id", author)
+
+	indexMapping := bleve.NewIndexMapping()
+	indexMapping.DefaultMapping = document
+
+	index, err := bleve.NewMemOnly(indexMapping)
+	if err != nil {
+		return nil, err
+	}
+	return &searchIndex{index: index}, nil
+}
+
+// add indexes a blog, or re-indexes it if it already is
+func (s *searchIndex) add(item *BlogItem) error {
+	return s.index.Index(item.ID.Hex(), searchDocument{
+		AuthorID: item.AuthorID,
+		Title:    item.Title,
+		Content:  item.Content,
+	})
+}
+
+func (s *searchIndex) remove(id primitive.ObjectID) error {
+	return s.index.Delete(id.Hex())
+}
+
+// search returns the ids of the best matches of q with their score, best first
+func (s *searchIndex) search(ctx context.Context, q SearchQuery) ([]primitive.ObjectID, []float64, error) {
+	// a word or a phrase can be in the title or the content
+	anyField := func(build func(text, field string) query.Query, text string) query.Query {
+		return bleve.NewDisjunctionQuery(build(text, "title"), build(text, "content"))
+	}
+	match := func(text, field string) query.Query {
+		m := bleve.NewMatchQuery(text)
+		m.SetField(field)
+		return m
+	}
+	phrase := func(text, field string) query.Query {
+		m := bleve.NewMatchPhraseQuery(text)
+		m.SetField(field)
+		return m
+	}
+
+	boolean := bleve.NewBooleanQuery()
+	for _, p := range q.Phrases {
+		boolean.AddMust(anyField(phrase, p))
+	}
+	for _, w := range q.Words {
+		boolean.AddShould(anyField(match, w))
+	}
+	// without phrases at least one word has to match, with phrases the words only help the ranking
+	if len(q.Phrases) == 0 {
+		boolean.SetMinShould(1)
+	}
+	if q.AuthorID != "" {
+		author := bleve.NewTermQuery(q.AuthorID)
+		author.SetField("author_id")
+		boolean.AddMust(author)
+	}
+
+	request := bleve.NewSearchRequestOptions(boolean, q.Limit, 0, false)
+	result, err := s.index.SearchInContext(ctx, request)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(result.Hits))
+	scores := make([]float64, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		id, err := primitive.ObjectIDFromHex(hit.ID)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+		scores = append(scores, hit.Score)
+	}
+	return ids, scores, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		text    string
+		words   []string
+		phrases []string
+	}{
+		{"grpc streams", []string{"grpc", "streams"}, nil},
+		{`"server streaming" grpc`, []string{"grpc"}, []string{"server streaming"}},
+		{`go  "two   spaces"  "" done`, []string{"go", "done"}, []string{"two spaces"}},
+		{`"unterminated phrase`, nil, []string{"unterminated phrase"}},
+		{"   ", nil, nil},
+	}
+	for _, tt := range tests {
+		q := parseSearchQuery(tt.text)
+		if !equalStrings(q.Words, tt.words) || !equalStrings(q.Phrases, tt.phrases) {
+			t.Errorf("parseSearchQuery(%q) returned words %q and phrases %q, want %q and %q", tt.text, q.Words, q.Phrases, tt.words, tt.phrases)
+		}
+	}
+}
+
+func TestHighlighterMark(t *testing.T) {
+	tests := []struct {
+		terms []string
+		text  string
+		want  string
+	}{
+		{[]string{"go"}, "Go is go", "<mark>Go</mark> is <mark>go</mark>"},
+		{[]string{"server", "server streaming"}, "a server streaming RPC", "a <mark>server streaming</mark> RPC"},
+		{[]string{"a.b"}, "a.b and axb", "<mark>a.b</mark> and axb"},
+		{[]string{"rust"}, "nothing to see", "nothing to see"},
+		{nil, "no terms", "no terms"},
+	}
+	for _, tt := range tests {
+		if got := newHighlighter(tt.terms).mark(tt.text); got != tt.want {
+			t.Errorf("mark(%q) with %q returned %q, want %q", tt.text, tt.terms, got, tt.want)
+		}
+	}
+}
+
+func TestHighlighterSnippet(t *testing.T) {
+	long := strings.Repeat("filler words ", 40)
+	tests := []struct {
+		name       string
+		text       string
+		wantPrefix string
+		wantSuffix string
+		contains   string
+	}{
+		{name: "short text", text: "all about grpc", wantPrefix: "all about <mark>grpc</mark>", wantSuffix: "</mark>"},
+		{name: "match far in", text: long + "grpc" + long, wantPrefix: "…", wantSuffix: "…", contains: "<mark>grpc</mark>"},
+		{name: "no match", text: long, wantPrefix: "filler", wantSuffix: "…"},
+		{name: "multi-byte characters", text: strings.Repeat("é", 300) + " grpc", wantPrefix: "…", contains: "<mark>grpc</mark>"},
+	}
+	h := newHighlighter([]string{"grpc"})
+	for _, tt := range tests {
+		got := h.snippet(tt.text)
+		if !strings.HasPrefix(got, tt.wantPrefix) || !strings.HasSuffix(got, tt.wantSuffix) || !strings.Contains(got, tt.contains) {
+			t.Errorf("%s: snippet is %q", tt.name, got)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("%s: snippet %q cuts a character", tt.name, got)
+		}
+		// give or take a character at both ends
+		if text := strings.NewReplacer(markStart, "", markEnd, "", "…", "").Replace(got); len(text) > snippetLength+2*(utf8.UTFMax-1) {
+			t.Errorf("%s: snippet keeps %d bytes of the content, want at most %d", tt.name, len(text), snippetLength)
+		}
+	}
+}
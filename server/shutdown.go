@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// handlerTracker knows when the handlers of the calls have all returned.
+// The gRPC server cancels the calls it stops without waiting for their handlers, which may still be writing to the store.
+type handlerTracker struct {
+	mu       sync.Mutex
+	running  int
+	draining bool
+	idle     chan struct{} // closed once draining with no handler running
+}
+
+func newHandlerTracker() *handlerTracker {
+	return &handlerTracker{idle: make(chan struct{})}
+}
+
+// enter counts a handler in, it returns false once the tracker is draining
+func (t *handlerTracker) enter() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.draining {
+		return false
+	}
+	t.running++
+	return true
+}
+
+func (t *handlerTracker) leave() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.running--
+	if t.draining && t.running == 0 {
+		close(t.idle)
+	}
+}
+
+// drain turns the calls still coming away, the returned channel is closed once the running handlers have all returned
+func (t *handlerTracker) drain() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.draining {
+		t.draining = true
+		if t.running == 0 {
+			close(t.idle)
+		}
+	}
+	return t.idle
+}
+
+// errShuttingDown rejects the calls reaching the handlers once the server is stopping
+var errShuttingDown = status.Errorf(codes.Unavailable, "The server is shutting down, retry on another one")
+
+// unaryInterceptor tracks the handler of every call. It runs first, the interceptors use the store too.
+func (t *handlerTracker) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !t.enter() {
+		return nil, errShuttingDown
+	}
+	defer t.leave()
+	return handler(ctx, req)
+}
+
+// streamInterceptor is unaryInterceptor for the streaming calls
+func (t *handlerTracker) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !t.enter() {
+		return errShuttingDown
+	}
+	defer t.leave()
+	return handler(srv, ss)
+}
+
+// awaitDrainDelay lets the calls come in for delay once the health service reports NOT_SERVING: the load balancers and
+// the clients watching it need a probe to stop sending them, the ones sent meanwhile would fail. Another signal cuts it short.
+func awaitDrainDelay(delay time.Duration, signals <-chan os.Signal) {
+	if delay <= 0 {
+		return
+	}
+	select {
+	case <-time.After(delay):
+	case sig := <-signals:
+		zap.L().Warn("Signal received during the drain delay, draining now", zap.Stringer("signal", sig))
+	}
+}
+
+// drainServers stops the gateway and the gRPC server gracefully: they take no new calls and let the running ones finish.
+// The calls still running when ctx is done, or when another signal comes, are cancelled.
+// It returns once every handler has returned, so the store can be closed.
+func drainServers(ctx context.Context, grpcServer *grpc.Server, httpServer *http.Server, handlers *handlerTracker, signals <-chan os.Signal) {
+	logger := zap.L()
+	stopped := make(chan struct{})
+	go func() {
+		// the requests of the gateway are calls of the gRPC server, they are drained first so none comes in after it stopped
+		if httpServer != nil {
+			if err := httpServer.Shutdown(ctx); err != nil {
+				httpServer.Close()
+			}
+		}
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		logger.Info("Drained every call")
+	case <-ctx.Done():
+		logger.Warn("Calls still running after the shutdown timeout, cancelling them")
+	case sig := <-signals:
+		logger.Warn("Signal received while draining, cancelling the calls still running", zap.Stringer("signal", sig))
+	}
+	// Stop is a no-op once GracefulStop is over, and makes it return otherwise
+	if httpServer != nil {
+		httpServer.Close()
+	}
+	grpcServer.Stop()
+	<-stopped
+	<-handlers.drain()
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// closed tells whether ch is closed already
+func closed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestHandlerTracker(t *testing.T) {
+	tests := []struct {
+		name      string
+		step      func(tr *handlerTracker) bool // the result of enter, true otherwise
+		wantOK    bool
+		wantIdle  bool
+		wantCount int
+	}{
+		{"first handler", (*handlerTracker).enter, true, false, 1},
+		{"second handler", (*handlerTracker).enter, true, false, 2},
+		{"first returns", func(tr *handlerTracker) bool { tr.leave(); return true }, true, false, 1},
+		{"draining", func(tr *handlerTracker) bool { tr.drain(); return true }, true, false, 1},
+		{"call during the drain", (*handlerTracker).enter, false, false, 1},
+		{"draining twice", func(tr *handlerTracker) bool { tr.drain(); return true }, true, false, 1},
+		{"last returns", func(tr *handlerTracker) bool { tr.leave(); return true }, true, true, 0},
+		{"call once drained", (*handlerTracker).enter, false, true, 0},
+	}
+	tr := newHandlerTracker()
+	for _, tt := range tests {
+		if ok := tt.step(tr); ok != tt.wantOK {
+			t.Errorf("%s: returned %v, want %v", tt.name, ok, tt.wantOK)
+		}
+		if idle := closed(tr.idle); idle != tt.wantIdle || tr.running != tt.wantCount {
+			t.Errorf("%s: %d handlers running and idle %v, want %d and %v", tt.name, tr.running, idle, tt.wantCount, tt.wantIdle)
+		}
+	}
+
+	// nothing to wait for when no handler runs
+	if idle := newHandlerTracker().drain(); !closed(idle) {
+		t.Error("drain of an idle tracker should be done at once")
+	}
+}
+
+func TestHandlerTrackerInterceptor(t *testing.T) {
+	tr := newHandlerTracker()
+	info := &grpc.UnaryServerInfo{FullMethod: blogServicePrefix + "UpdateBlog"}
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := tr.unaryInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+		done <- err
+	}()
+	<-started
+
+	drained := tr.drain()
+	if _, err := tr.unaryInterceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		t.Error("a call reached its handler during the drain")
+		return nil, nil
+	}); status.Code(err) != codes.Unavailable {
+		t.Errorf("a call during the drain returned %v, want Unavailable", err)
+	}
+	if closed(drained) {
+		t.Error("drained with a handler still running")
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("the running call returned %v", err)
+	}
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Error("not drained once the handler returned")
+	}
+}
+
+func TestDrainServers(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		signal  bool
+		// the handler keeps running a while once its call is cancelled
+		stubborn bool
+	}{
+		{name: "calls finish", timeout: time.Second},
+		{name: "shutdown timeout", timeout: 50 * time.Millisecond, stubborn: true},
+		{name: "second signal", timeout: time.Hour, signal: true, stubborn: true},
+	}
+	for _, tt := range tests {
+		handlers := newHandlerTracker()
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		running := make(chan struct{})
+		returned := make(chan struct{})
+		server := grpc.NewServer(grpc.UnaryInterceptor(handlers.unaryInterceptor))
+		healthpb.RegisterHealthServer(server, &slowHealth{Server: health.NewServer(), running: running, returned: returned, stubborn: tt.stubborn})
+		go server.Serve(lis)
+
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+		if err != nil {
+			t.Fatal(err)
+		}
+		go healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		<-running
+
+		signals := make(chan os.Signal, 1)
+		if tt.signal {
+			signals <- os.Interrupt
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+		start := time.Now()
+		drainServers(ctx, server, nil, handlers, signals)
+		took := time.Since(start)
+		cancel()
+		conn.Close()
+
+		// drainServers returns once every handler did, never before
+		if !closed(returned) {
+			t.Errorf("%s: drainServers returned before the handler", tt.name)
+		}
+		if took > time.Second {
+			t.Errorf("%s: drainServers took %v", tt.name, took)
+		}
+	}
+}
+
+// slowHealth answers its checks after 100ms, or 100ms after their call is cancelled when it is stubborn
+type slowHealth struct {
+	*health.Server
+	running, returned chan struct{}
+	stubborn          bool
+}
+
+func (h *slowHealth) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	defer close(h.returned)
+	close(h.running)
+	select {
+	case <-time.After(100 * time.Millisecond):
+	case <-ctx.Done():
+		if h.stubborn {
+			// like a write to the store finishing after the cancellation
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	return h.Server.Check(ctx, req)
+}
+
+func TestAwaitDrainDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		delay  time.Duration
+		signal bool
+		min    time.Duration
+		max    time.Duration
+	}{
+		{name: "no delay", delay: 0, max: 10 * time.Millisecond},
+		{name: "delay", delay: 50 * time.Millisecond, min: 50 * time.Millisecond, max: time.Second},
+		{name: "cut short by a signal", delay: time.Hour, signal: true, max: time.Second},
+	}
+	for _, tt := range tests {
+		signals := make(chan os.Signal, 1)
+		if tt.signal {
+			signals <- os.Interrupt
+		}
+		start := time.Now()
+		awaitDrainDelay(tt.delay, signals)
+		if took := time.Since(start); took < tt.min || took > tt.max {
+			t.Errorf("%s: awaitDrainDelay(%v) took %v, want between %v and %v", tt.name, tt.delay, took, tt.min, tt.max)
+		}
+	}
+}
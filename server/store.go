@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrBlogNotFound is returned by a BlogStore when no blog has the requested id
+var ErrBlogNotFound = errors.New("blog not found")
+
+// ErrRevisionNotFound is returned by a BlogStore when a blog has no revision matching the request
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// VersionMismatchError is returned by a BlogStore when a write expected the blog at another version.
+// The version of a blog is the number of its latest revision.
+type VersionMismatchError struct {
+	ID       primitive.ObjectID
+	Expected int64
+	Current  int64
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("blog %s is at version %d, not %d", e.ID.Hex(), e.Current, e.Expected)
+}
+
+// checkVersion returns a *VersionMismatchError if expected is set and differs from the version of current
+func checkVersion(expected int64, current *BlogItem) error {
+	if expected != 0 && expected != current.Revision {
+		return &VersionMismatchError{ID: current.ID, Expected: expected, Current: current.Revision}
+	}
+	return nil
+}
+
+// StatusError is returned by a BlogStore when a status change finds the blog in an unexpected status
+type StatusError struct {
+	ID      primitive.ObjectID
+	Current string
+	To      string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("blog %s can't go from %s to %s", e.ID.Hex(), e.Current, e.To)
+}
+
+// statuses of the editorial workflow, as stored
+const (
+	statusDraft     = "draft"
+	statusInReview  = "in_review"
+	statusPublished = "published"
+	statusArchived  = "archived"
+)
+
+// BlogItem is the storage representation of a blog, shared by every BlogStore
+type BlogItem struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty"`
+	AuthorID string             `bson:"author_id"`
+	Content  string             `bson:"content"`
+	Title    string             `bson:"title"`
+
+	// Revision is the number of the last revision recorded for this blog, it is set by the store.
+	// UpdatedBy and UpdatedAt are filled by the caller and copied into that revision.
+	Revision  int64     `bson:"revision"`
+	UpdatedBy string    `bson:"updated_by"`
+	UpdatedAt time.Time `bson:"updated_at"`
+
+	// DeletedAt is set while the blog is in the trash
+	DeletedAt *time.Time `bson:"deleted_at,omitempty"`
+
+	// Status is one of the status* constants, use status() to read it
+	Status string `bson:"status,omitempty"`
+	// PublishAt is set while an in review blog waits for its scheduled publication
+	PublishAt *time.Time `bson:"publish_at,omitempty"`
+
+	// Tenant is set by the stores keeping several tenants in the same place, see mongoStore
+	Tenant string `bson:"tenant,omitempty"`
+}
+
+// status returns the workflow status of the blog,
+// blogs written before the workflow existed have none and were live, so they count as published
+func (item *BlogItem) status() string {
+	if item.Status == "" {
+		return statusPublished
+	}
+	return item.Status
+}
+
+// RevisionItem is an immutable snapshot of a blog, recorded by the store on every create, update and status change
+type RevisionItem struct {
+	BlogID    primitive.ObjectID `bson:"blog_id"`
+	Revision  int64              `bson:"revision"`
+	AuthorID  string             `bson:"author_id"`
+	Content   string             `bson:"content"`
+	Title     string             `bson:"title"`
+	EditorID  string             `bson:"editor_id"`
+	CreatedAt time.Time          `bson:"created_at"`
+	Tenant    string             `bson:"tenant,omitempty"`
+}
+
+// blogFields are the fields of a blog a client can write, named like in the proto and BSON documents
+var blogFields = []string{"author_id", "title", "content"}
+
+// ListQuery holds the filters, ordering and position of a listing
+type ListQuery struct {
+	AuthorID    string
+	TitlePrefix string
+	SortBy      blogpb.ListBlogReq_SortField
+	Desc        bool
+	Limit       int64       // 0 means no limit
+	After       *listCursor // nil to start from the beginning
+	Deleted     bool        // list the trash instead of the live blogs
+	Status      string      // only blogs in this status, blank for all
+}
+
+// BlogStore is everything BlogServiceServer needs from a database.
+// Implementations must be safe for concurrent use, since every gRPC call runs in its own goroutine.
+type BlogStore interface {
+	// Create stores a new blog and records its first revision, the returned item has its ID filled
+	Create(ctx context.Context, item *BlogItem) (*BlogItem, error)
+	// Get returns ErrBlogNotFound if there is no blog with this id, or if it is in the trash.
+	// Unless stated otherwise, every method treats the blogs in the trash as missing.
+	Get(ctx context.Context, id primitive.ObjectID) (*BlogItem, error)
+	// Update writes the given blogFields of item (plus UpdatedBy and UpdatedAt) to the blog with item.ID,
+	// records a new revision and returns the updated blog, or ErrBlogNotFound.
+	// If expectedVersion isn't 0 and the blog is at another version, nothing is written and a *VersionMismatchError is returned.
+	Update(ctx context.Context, item *BlogItem, fields []string, expectedVersion int64) (*BlogItem, error)
+	// Delete moves the blog to the trash, marked as deleted at the given time, or returns ErrBlogNotFound.
+	// expectedVersion is checked like for Update.
+	Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64, at time.Time) error
+	// Undelete takes a blog out of the trash, ErrBlogNotFound is returned if it isn't in there
+	Undelete(ctx context.Context, id primitive.ObjectID) (*BlogItem, error)
+	// GetDeleted is Get for the blogs in the trash, ErrBlogNotFound is returned if it isn't in there
+	GetDeleted(ctx context.Context, id primitive.ObjectID) (*BlogItem, error)
+	// Purge removes a blog in the trash along with its revisions, ErrBlogNotFound is returned if it isn't in there
+	Purge(ctx context.Context, id primitive.ObjectID) error
+	// SetStatus moves a blog from status from to status to, atomically, and cancels any scheduled publication.
+	// Like an update, the change makes a new version of the blog and records its revision, made by editor at the given time
+	// (a blank editor keeps the last one). A *StatusError is returned if the blog isn't in status from.
+	SetStatus(ctx context.Context, id primitive.ObjectID, from, to, editor string, at time.Time) (*BlogItem, error)
+	// SchedulePublish records that an in review blog is to be published at publishAt, a new version made by editor at the given time.
+	// A *StatusError is returned if the blog isn't in review.
+	SchedulePublish(ctx context.Context, id primitive.ObjectID, publishAt time.Time, editor string, at time.Time) (*BlogItem, error)
+	// PublishDue publishes the blogs whose scheduled publication is at or before now and returns them,
+	// each publication is a new version made by the last editor at now.
+	// Each blog is claimed atomically, so servers sharing a database never publish the same blog twice.
+	PublishDue(ctx context.Context, now time.Time) ([]*BlogItem, error)
+	// PurgeDeletedBefore purges every blog moved to the trash before the given time and returns how many were
+	PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error)
+	// List calls fn for every blog matching q, in order, and stops at the first error returned by fn
+	List(ctx context.Context, q ListQuery, fn func(*BlogItem) error) error
+	// Search calls fn for the best full-text matches of q in the titles and contents, best first, with their score
+	Search(ctx context.Context, q SearchQuery, fn func(item *BlogItem, score float64) error) error
+
+	// ListRevisions calls fn for every revision of a blog, oldest first
+	ListRevisions(ctx context.Context, id primitive.ObjectID, fn func(*RevisionItem) error) error
+	// GetRevision returns ErrRevisionNotFound if the blog has no such revision
+	GetRevision(ctx context.Context, id primitive.ObjectID, revision int64) (*RevisionItem, error)
+	// GetRevisionAt returns the last revision recorded at or before asOf, or ErrRevisionNotFound
+	GetRevisionAt(ctx context.Context, id primitive.ObjectID, asOf time.Time) (*RevisionItem, error)
+}
+
+// ErrAPIKeyNotFound is returned by an APIKeyStore when no key has the requested id or hash
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// ErrAPIKeyRevoked is returned by an APIKeyStore when changing a key that was revoked
+var ErrAPIKeyRevoked = errors.New("api key is revoked")
+
+// APIKeyItem is the storage representation of an API key, the secret itself is never stored, only its hash
+type APIKeyItem struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty"`
+	Name   string             `bson:"name"`
+	Scopes []string           `bson:"scopes"`
+	// OwnerID, Roles and Tenant are the ones of the user who created the key, calls made with it act as this user
+	OwnerID string   `bson:"owner_id"`
+	Roles   []string `bson:"roles,omitempty"`
+	Tenant  string   `bson:"tenant,omitempty"`
+	// Hash is the hex SHA-256 of the secret, Prefix its first characters
+	Hash      string     `bson:"hash"`
+	Prefix    string     `bson:"prefix"`
+	CreatedAt time.Time  `bson:"created_at"`
+	RotatedAt *time.Time `bson:"rotated_at,omitempty"`
+	RevokedAt *time.Time `bson:"revoked_at,omitempty"`
+}
+
+// APIKeyStore keeps the API keys, every BlogStore of the server is one too.
+// Implementations must be safe for concurrent use.
+type APIKeyStore interface {
+	// CreateAPIKey stores a new key, the returned item has its ID filled
+	CreateAPIKey(ctx context.Context, item *APIKeyItem) (*APIKeyItem, error)
+	// GetAPIKey returns ErrAPIKeyNotFound if there is no key with this id, revoked keys are returned
+	GetAPIKey(ctx context.Context, id primitive.ObjectID) (*APIKeyItem, error)
+	// FindAPIKey returns the key whose secret has this hash, revoked or not, or ErrAPIKeyNotFound
+	FindAPIKey(ctx context.Context, hash string) (*APIKeyItem, error)
+	// ListAPIKeys calls fn for every key of an owner of a tenant, oldest first, and stops at the first error returned by fn
+	ListAPIKeys(ctx context.Context, tenant, ownerID string, fn func(*APIKeyItem) error) error
+	// RevokeAPIKey marks a key as revoked at the given time and returns it.
+	// ErrAPIKeyNotFound is returned if there is no such key and ErrAPIKeyRevoked if it was revoked already.
+	RevokeAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) (*APIKeyItem, error)
+	// RotateAPIKey replaces the hash and prefix of a key, rotated at the given time, and returns it.
+	// Errors are the ones of RevokeAPIKey.
+	RotateAPIKey(ctx context.Context, id primitive.ObjectID, hash, prefix string, at time.Time) (*APIKeyItem, error)
+}
+
+// toProto converts a stored blog to its protobuf message
+func (item *BlogItem) toProto() *blogpb.Blog {
+	blog := &blogpb.Blog{
+		Id:       item.ID.Hex(),
+		AuthorId: item.AuthorID,
+		Title:    item.Title,
+		Content:  item.Content,
+		Version:  item.Revision,
+		Status:   statusToProto[item.status()],
+	}
+	if item.PublishAt != nil {
+		blog.PublishAt = timestamppb.New(*item.PublishAt)
+	}
+	return blog
+}
+
+// applyFields copies the given blogFields and the update metadata of src onto dst
+func applyFields(dst, src *BlogItem, fields []string) {
+	for _, field := range fields {
+		switch field {
+		case "author_id":
+			dst.AuthorID = src.AuthorID
+		case "title":
+			dst.Title = src.Title
+		case "content":
+			dst.Content = src.Content
+		}
+	}
+	dst.UpdatedBy = src.UpdatedBy
+	dst.UpdatedAt = src.UpdatedAt
+}
+
+// nextVersion turns item into its next version, changed by editor at the given time, a blank editor keeps the last one.
+// The caller changes the other fields and records the revision.
+func nextVersion(item *BlogItem, editor string, at time.Time) {
+	item.Revision++
+	if editor != "" {
+		item.UpdatedBy = editor
+	}
+	item.UpdatedAt = at
+}
+
+// newRevision snapshots item as revision item.Revision
+func newRevision(item *BlogItem) RevisionItem {
+	return RevisionItem{
+		BlogID:    item.ID,
+		Revision:  item.Revision,
+		AuthorID:  item.AuthorID,
+		Content:   item.Content,
+		Title:     item.Title,
+		EditorID:  item.UpdatedBy,
+		CreatedAt: item.UpdatedAt,
+		Tenant:    item.Tenant,
+	}
+}
+
+// toProto converts a stored revision to its protobuf message
+func (rev *RevisionItem) toProto() *blogpb.BlogRevision {
+	return &blogpb.BlogRevision{
+		Revision: rev.Revision,
+		Blog: &blogpb.Blog{
+			Id:       rev.BlogID.Hex(),
+			AuthorId: rev.AuthorID,
+			Title:    rev.Title,
+			Content:  rev.Content,
+			Version:  rev.Revision,
+		},
+		EditorId:  rev.EditorID,
+		CreatedAt: timestamppb.New(rev.CreatedAt),
+	}
+}
+
+// toProto converts a stored API key to its protobuf message, which never has the hash
+func (item *APIKeyItem) toProto() *blogpb.ApiKey {
+	key := &blogpb.ApiKey{
+		Id:        item.ID.Hex(),
+		Name:      item.Name,
+		Scopes:    item.Scopes,
+		OwnerId:   item.OwnerID,
+		Prefix:    item.Prefix,
+		CreatedAt: timestamppb.New(item.CreatedAt),
+	}
+	if item.RotatedAt != nil {
+		key.RotatedAt = timestamppb.New(*item.RotatedAt)
+	}
+	if item.RevokedAt != nil {
+		key.RevokedAt = timestamppb.New(*item.RevokedAt)
+	}
+	return key
+}
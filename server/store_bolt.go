@@ -0,0 +1,678 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// blogsBucket holds one BSON encoded BlogItem per blog, keyed by the 12 bytes of its ObjectId
+var blogsBucket = []byte("blogs")
+
+// revisionsBucket holds one BSON encoded RevisionItem per revision, keyed by the blog ObjectId
+// followed by the big endian revision number, so the revisions of a blog are contiguous and in order
+var revisionsBucket = []byte("revisions")
+
+// apiKeysBucket holds one BSON encoded APIKeyItem per API key, keyed by its ObjectId,
+// and apiKeyHashesBucket the ObjectId of the key of every hash
+var apiKeysBucket = []byte("api_keys")
+var apiKeyHashesBucket = []byte("api_key_hashes")
+
+// boltStore keeps the blogs in a single bbolt file, for small installs that don't want to run MongoDB.
+// Ids are generated as ObjectIds, so clients see the same 24 hex chars ids as with the Mongo store.
+type boltStore struct {
+	db *bolt.DB
+	// index is rebuilt from the file on start, then updated after every committed write
+	index *searchIndex
+}
+
+// newBoltStore opens (or creates) the database file at path
+func newBoltStore(path string) (*boltStore, error) {
+	// a second server on the same file would wait for the lock forever, give up instead
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{blogsBucket, revisionsBucket, apiKeysBucket, apiKeyHashesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	b := &boltStore{db: db}
+	if err := b.buildIndex(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not index %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// newBoltTenants keeps the blogs of every tenant in a file of its own next to path, blog.acme.db for tenant acme of blog.db.
+// The file at path keeps the API keys of every tenant.
+func newBoltTenants(path string) *tenantStores {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	return newTenantStores(func(ctx context.Context, tenant string) (BlogStore, error) {
+		return newBoltStore(base + "." + tenant + ext)
+	}, func(ctx context.Context) ([]string, error) {
+		files, err := filepath.Glob(base + ".*" + ext)
+		var names []string
+		for _, file := range files {
+			if name := strings.TrimSuffix(strings.TrimPrefix(file, base+"."), ext); file != path && !strings.Contains(name, ".") {
+				names = append(names, name)
+			}
+		}
+		return names, err
+	})
+}
+
+// buildIndex indexes every blog that isn't in the trash
+func (b *boltStore) buildIndex() error {
+	index, err := newSearchIndex()
+	if err != nil {
+		return err
+	}
+
+	err = b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(blogsBucket).ForEach(func(k, v []byte) error {
+			data := BlogItem{}
+			if err := bson.Unmarshal(v, &data); err != nil {
+				return fmt.Errorf("could not decode blog %x: %w", k, err)
+			}
+			if data.DeletedAt != nil {
+				return nil
+			}
+			return index.add(&data)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	b.index = index
+	return nil
+}
+
+// Close releases the database file
+func (b *boltStore) Close() error {
+	return b.db.Close()
+}
+
+func (b *boltStore) Create(ctx context.Context, item *BlogItem) (*BlogItem, error) {
+	created := *item
+	created.ID = primitive.NewObjectID()
+	created.Revision = 1
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		if err := putItem(tx, &created); err != nil {
+			return err
+		}
+		return putRevision(tx, newRevision(&created))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := b.index.add(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (b *boltStore) Get(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	var data *BlogItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		data, err = getLive(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (b *boltStore) Update(ctx context.Context, item *BlogItem, fields []string, expectedVersion int64) (*BlogItem, error) {
+	var updated BlogItem
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		current, err := getLive(tx, item.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(expectedVersion, current); err != nil {
+			return err
+		}
+		updated = *current
+		applyFields(&updated, item, fields)
+		updated.Revision = current.Revision + 1
+		if err := putItem(tx, &updated); err != nil {
+			return err
+		}
+		return putRevision(tx, newRevision(&updated))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := b.index.add(&updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (b *boltStore) Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64, at time.Time) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		current, err := getLive(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(expectedVersion, current); err != nil {
+			return err
+		}
+		current.DeletedAt = &at
+		return putItem(tx, current)
+	})
+	if err != nil {
+		return err
+	}
+	return b.index.remove(id)
+}
+
+func (b *boltStore) Undelete(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	var data *BlogItem
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		data, err = getItem(tx, id)
+		if err != nil {
+			return err
+		}
+		if data.DeletedAt == nil {
+			return ErrBlogNotFound
+		}
+		data.DeletedAt = nil
+		return putItem(tx, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := b.index.add(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (b *boltStore) GetDeleted(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	var data *BlogItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		data, err = getItem(tx, id)
+		if err != nil {
+			return err
+		}
+		if data.DeletedAt == nil {
+			return ErrBlogNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (b *boltStore) Purge(ctx context.Context, id primitive.ObjectID) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		data, err := getItem(tx, id)
+		if err != nil {
+			return err
+		}
+		if data.DeletedAt == nil {
+			return ErrBlogNotFound
+		}
+		return purgeItem(tx, id)
+	})
+}
+
+func (b *boltStore) SetStatus(ctx context.Context, id primitive.ObjectID, from, to, editor string, at time.Time) (*BlogItem, error) {
+	var data *BlogItem
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		data, err = getLive(tx, id)
+		if err != nil {
+			return err
+		}
+		if data.status() != from {
+			return &StatusError{ID: id, Current: data.status(), To: to}
+		}
+		data.Status = to
+		data.PublishAt = nil
+		nextVersion(data, editor, at)
+		if err := putItem(tx, data); err != nil {
+			return err
+		}
+		return putRevision(tx, newRevision(data))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (b *boltStore) SchedulePublish(ctx context.Context, id primitive.ObjectID, publishAt time.Time, editor string, at time.Time) (*BlogItem, error) {
+	var data *BlogItem
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		data, err = getLive(tx, id)
+		if err != nil {
+			return err
+		}
+		if data.status() != statusInReview {
+			return &StatusError{ID: id, Current: data.status(), To: statusPublished}
+		}
+		data.PublishAt = &publishAt
+		nextVersion(data, editor, at)
+		if err := putItem(tx, data); err != nil {
+			return err
+		}
+		return putRevision(tx, newRevision(data))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (b *boltStore) PublishDue(ctx context.Context, now time.Time) ([]*BlogItem, error) {
+	var published []*BlogItem
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		// writing while iterating is unsafe, so collect the due blogs first
+		err := tx.Bucket(blogsBucket).ForEach(func(k, v []byte) error {
+			data := &BlogItem{}
+			if err := bson.Unmarshal(v, data); err != nil {
+				return fmt.Errorf("could not decode blog %x: %w", k, err)
+			}
+			if isDue(data, now) {
+				published = append(published, data)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, data := range published {
+			data.Status = statusPublished
+			data.PublishAt = nil
+			nextVersion(data, "", now)
+			if err := putItem(tx, data); err != nil {
+				return err
+			}
+			if err := putRevision(tx, newRevision(data)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return published, nil
+}
+
+func (b *boltStore) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		// deleting while iterating skips keys, so collect the ids first
+		var ids []primitive.ObjectID
+		err := tx.Bucket(blogsBucket).ForEach(func(k, v []byte) error {
+			data := BlogItem{}
+			if err := bson.Unmarshal(v, &data); err != nil {
+				return fmt.Errorf("could not decode blog %x: %w", k, err)
+			}
+			if data.DeletedAt != nil && data.DeletedAt.Before(before) {
+				ids = append(ids, data.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := purgeItem(tx, id); err != nil {
+				return err
+			}
+		}
+		purged = int64(len(ids))
+		return nil
+	})
+	return purged, err
+}
+
+func (b *boltStore) List(ctx context.Context, q ListQuery, fn func(*BlogItem) error) error {
+	// a few thousand blogs fit in memory, so filter in a read transaction and send once it's closed,
+	// a slow client must not keep the transaction (and the file from growing back) open
+	var matches []BlogItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(blogsBucket).ForEach(func(k, v []byte) error {
+			data := BlogItem{}
+			if err := bson.Unmarshal(v, &data); err != nil {
+				return fmt.Errorf("could not decode blog %x: %w", k, err)
+			}
+			if matchesQuery(&data, q) {
+				matches = append(matches, data)
+			}
+			return ctx.Err()
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return sendItems(ctx, matches, q, fn)
+}
+
+func (b *boltStore) Search(ctx context.Context, q SearchQuery, fn func(item *BlogItem, score float64) error) error {
+	ids, scores, err := b.index.search(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		// the blog may have been deleted since the index was searched
+		data, err := b.Get(ctx, id)
+		if err == ErrBlogNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(data, scores[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *boltStore) ListRevisions(ctx context.Context, id primitive.ObjectID, fn func(*RevisionItem) error) error {
+	revisions, err := b.revisions(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for i := range revisions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&revisions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *boltStore) GetRevision(ctx context.Context, id primitive.ObjectID, revision int64) (*RevisionItem, error) {
+	rev := &RevisionItem{}
+	err := b.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(revisionsBucket).Get(revisionKey(id, revision))
+		if v == nil {
+			return ErrRevisionNotFound
+		}
+		return bson.Unmarshal(v, rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+func (b *boltStore) GetRevisionAt(ctx context.Context, id primitive.ObjectID, asOf time.Time) (*RevisionItem, error) {
+	revisions, err := b.revisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return revisionAt(revisions, asOf)
+}
+
+// revisions reads all the revisions of a blog, oldest first
+func (b *boltStore) revisions(ctx context.Context, id primitive.ObjectID) ([]RevisionItem, error) {
+	var revisions []RevisionItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(revisionsBucket).Cursor()
+		for k, v := c.Seek(id[:]); k != nil && bytes.HasPrefix(k, id[:]); k, v = c.Next() {
+			rev := RevisionItem{}
+			if err := bson.Unmarshal(v, &rev); err != nil {
+				return fmt.Errorf("could not decode revision %x: %w", k, err)
+			}
+			revisions = append(revisions, rev)
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func revisionKey(id primitive.ObjectID, revision int64) []byte {
+	key := make([]byte, len(id)+8)
+	copy(key, id[:])
+	binary.BigEndian.PutUint64(key[len(id):], uint64(revision))
+	return key
+}
+
+func putRevision(tx *bolt.Tx, rev RevisionItem) error {
+	v, err := bson.Marshal(rev)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(revisionsBucket).Put(revisionKey(rev.BlogID, rev.Revision), v)
+}
+
+// purgeItem removes a blog and its revisions
+func purgeItem(tx *bolt.Tx, id primitive.ObjectID) error {
+	if err := tx.Bucket(blogsBucket).Delete(id[:]); err != nil {
+		return err
+	}
+
+	// deleting while iterating skips keys, so collect copies of them first
+	var keys [][]byte
+	c := tx.Bucket(revisionsBucket).Cursor()
+	for k, _ := c.Seek(id[:]); k != nil && bytes.HasPrefix(k, id[:]); k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	for _, k := range keys {
+		if err := tx.Bucket(revisionsBucket).Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getLive is getItem for the blogs that aren't in the trash
+func getLive(tx *bolt.Tx, id primitive.ObjectID) (*BlogItem, error) {
+	data, err := getItem(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if data.DeletedAt != nil {
+		return nil, ErrBlogNotFound
+	}
+	return data, nil
+}
+
+func getItem(tx *bolt.Tx, id primitive.ObjectID) (*BlogItem, error) {
+	v := tx.Bucket(blogsBucket).Get(id[:])
+	if v == nil {
+		return nil, ErrBlogNotFound
+	}
+
+	data := &BlogItem{}
+	if err := bson.Unmarshal(v, data); err != nil {
+		return nil, fmt.Errorf("could not decode blog %s: %w", id.Hex(), err)
+	}
+	return data, nil
+}
+
+func putItem(tx *bolt.Tx, item *BlogItem) error {
+	v, err := bson.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(blogsBucket).Put(item.ID[:], v)
+}
+
+func (b *boltStore) CreateAPIKey(ctx context.Context, item *APIKeyItem) (*APIKeyItem, error) {
+	created := *item
+	created.ID = primitive.NewObjectID()
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(apiKeyHashesBucket).Put([]byte(created.Hash), created.ID[:]); err != nil {
+			return err
+		}
+		return putAPIKey(tx, &created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (b *boltStore) GetAPIKey(ctx context.Context, id primitive.ObjectID) (*APIKeyItem, error) {
+	var key *APIKeyItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		key, err = getAPIKey(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (b *boltStore) FindAPIKey(ctx context.Context, hash string) (*APIKeyItem, error) {
+	var key *APIKeyItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(apiKeyHashesBucket).Get([]byte(hash))
+		if v == nil {
+			return ErrAPIKeyNotFound
+		}
+		var id primitive.ObjectID
+		copy(id[:], v)
+
+		var err error
+		key, err = getAPIKey(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (b *boltStore) ListAPIKeys(ctx context.Context, tenant, ownerID string, fn func(*APIKeyItem) error) error {
+	// ObjectIds start with their creation time, so the bucket is in creation order
+	var keys []APIKeyItem
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(apiKeysBucket).ForEach(func(k, v []byte) error {
+			key := APIKeyItem{}
+			if err := bson.Unmarshal(v, &key); err != nil {
+				return fmt.Errorf("could not decode api key %x: %w", k, err)
+			}
+			if key.Tenant == tenant && key.OwnerID == ownerID {
+				keys = append(keys, key)
+			}
+			return ctx.Err()
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range keys {
+		if err := fn(&keys[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *boltStore) RevokeAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) (*APIKeyItem, error) {
+	return b.changeAPIKey(id, func(tx *bolt.Tx, key *APIKeyItem) error {
+		key.RevokedAt = &at
+		return nil
+	})
+}
+
+func (b *boltStore) RotateAPIKey(ctx context.Context, id primitive.ObjectID, hash, prefix string, at time.Time) (*APIKeyItem, error) {
+	return b.changeAPIKey(id, func(tx *bolt.Tx, key *APIKeyItem) error {
+		hashes := tx.Bucket(apiKeyHashesBucket)
+		if err := hashes.Delete([]byte(key.Hash)); err != nil {
+			return err
+		}
+		if err := hashes.Put([]byte(hash), id[:]); err != nil {
+			return err
+		}
+		key.Hash, key.Prefix, key.RotatedAt = hash, prefix, &at
+		return nil
+	})
+}
+
+// changeAPIKey applies change to a key that isn't revoked and saves it, in a single transaction
+func (b *boltStore) changeAPIKey(id primitive.ObjectID, change func(tx *bolt.Tx, key *APIKeyItem) error) (*APIKeyItem, error) {
+	var key *APIKeyItem
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		key, err = getAPIKey(tx, id)
+		if err != nil {
+			return err
+		}
+		if key.RevokedAt != nil {
+			return ErrAPIKeyRevoked
+		}
+		if err := change(tx, key); err != nil {
+			return err
+		}
+		return putAPIKey(tx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func getAPIKey(tx *bolt.Tx, id primitive.ObjectID) (*APIKeyItem, error) {
+	v := tx.Bucket(apiKeysBucket).Get(id[:])
+	if v == nil {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	key := &APIKeyItem{}
+	if err := bson.Unmarshal(v, key); err != nil {
+		return nil, fmt.Errorf("could not decode api key %s: %w", id.Hex(), err)
+	}
+	return key, nil
+}
+
+func putAPIKey(tx *bolt.Tx, key *APIKeyItem) error {
+	v, err := bson.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(apiKeysBucket).Put(key.ID[:], v)
+}
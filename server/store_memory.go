@@ -0,0 +1,475 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryStore keeps the blogs in a map, for local development and CI runs without a database.
+// Nothing survives a restart.
+type memoryStore struct {
+	mu        sync.RWMutex
+	blogs     map[primitive.ObjectID]BlogItem
+	revisions map[primitive.ObjectID][]RevisionItem // oldest first
+	index     *searchIndex                          // updated along with blogs, under mu
+
+	apiKeys      map[primitive.ObjectID]APIKeyItem
+	apiKeyHashes map[string]primitive.ObjectID // id of the key of every hash
+}
+
+func newMemoryStore() (*memoryStore, error) {
+	index, err := newSearchIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return &memoryStore{
+		blogs:     make(map[primitive.ObjectID]BlogItem),
+		revisions: make(map[primitive.ObjectID][]RevisionItem),
+		index:     index,
+
+		apiKeys:      make(map[primitive.ObjectID]APIKeyItem),
+		apiKeyHashes: make(map[string]primitive.ObjectID),
+	}, nil
+}
+
+func (m *memoryStore) Create(ctx context.Context, item *BlogItem) (*BlogItem, error) {
+	// same ids as the ones MongoDB would generate, so clients can't tell the difference
+	created := *item
+	created.ID = primitive.NewObjectID()
+	created.Revision = 1
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// indexed first, a blog the index refused is not stored
+	if err := m.index.add(&created); err != nil {
+		return nil, err
+	}
+	m.blogs[created.ID] = created
+	m.revisions[created.ID] = []RevisionItem{newRevision(&created)}
+	return &created, nil
+}
+
+func (m *memoryStore) Get(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	m.mu.RLock()
+	data, ok := m.live(id)
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrBlogNotFound
+	}
+	return &data, nil
+}
+
+// live returns the blog with this id unless it is missing or in the trash, m.mu must be held
+func (m *memoryStore) live(id primitive.ObjectID) (BlogItem, bool) {
+	data, ok := m.blogs[id]
+	if !ok || data.DeletedAt != nil {
+		return BlogItem{}, false
+	}
+	return data, true
+}
+
+func (m *memoryStore) Update(ctx context.Context, item *BlogItem, fields []string, expectedVersion int64) (*BlogItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.live(item.ID)
+	if !ok {
+		return nil, ErrBlogNotFound
+	}
+	if err := checkVersion(expectedVersion, &current); err != nil {
+		return nil, err
+	}
+	updated := current
+	applyFields(&updated, item, fields)
+	updated.Revision = current.Revision + 1
+	if err := m.index.add(&updated); err != nil {
+		return nil, err
+	}
+	m.blogs[item.ID] = updated
+	m.revisions[item.ID] = append(m.revisions[item.ID], newRevision(&updated))
+	return &updated, nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.live(id)
+	if !ok {
+		return ErrBlogNotFound
+	}
+	if err := checkVersion(expectedVersion, &current); err != nil {
+		return err
+	}
+	if err := m.index.remove(id); err != nil {
+		return err
+	}
+	current.DeletedAt = &at
+	m.blogs[id] = current
+	return nil
+}
+
+func (m *memoryStore) Undelete(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.blogs[id]
+	if !ok || data.DeletedAt == nil {
+		return nil, ErrBlogNotFound
+	}
+	data.DeletedAt = nil
+	if err := m.index.add(&data); err != nil {
+		return nil, err
+	}
+	m.blogs[id] = data
+	return &data, nil
+}
+
+func (m *memoryStore) GetDeleted(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	m.mu.RLock()
+	data, ok := m.blogs[id]
+	m.mu.RUnlock()
+
+	if !ok || data.DeletedAt == nil {
+		return nil, ErrBlogNotFound
+	}
+	return &data, nil
+}
+
+func (m *memoryStore) Purge(ctx context.Context, id primitive.ObjectID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.blogs[id]
+	if !ok || data.DeletedAt == nil {
+		return ErrBlogNotFound
+	}
+	delete(m.blogs, id)
+	delete(m.revisions, id)
+	return nil
+}
+
+func (m *memoryStore) SetStatus(ctx context.Context, id primitive.ObjectID, from, to, editor string, at time.Time) (*BlogItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.live(id)
+	if !ok {
+		return nil, ErrBlogNotFound
+	}
+	if current.status() != from {
+		return nil, &StatusError{ID: id, Current: current.status(), To: to}
+	}
+	current.Status = to
+	current.PublishAt = nil
+	nextVersion(&current, editor, at)
+	m.blogs[id] = current
+	m.revisions[id] = append(m.revisions[id], newRevision(&current))
+	return &current, nil
+}
+
+func (m *memoryStore) SchedulePublish(ctx context.Context, id primitive.ObjectID, publishAt time.Time, editor string, at time.Time) (*BlogItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.live(id)
+	if !ok {
+		return nil, ErrBlogNotFound
+	}
+	if current.status() != statusInReview {
+		return nil, &StatusError{ID: id, Current: current.status(), To: statusPublished}
+	}
+	current.PublishAt = &publishAt
+	nextVersion(&current, editor, at)
+	m.blogs[id] = current
+	m.revisions[id] = append(m.revisions[id], newRevision(&current))
+	return &current, nil
+}
+
+func (m *memoryStore) PublishDue(ctx context.Context, now time.Time) ([]*BlogItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var published []*BlogItem
+	for id, data := range m.blogs {
+		if isDue(&data, now) {
+			updated := data
+			updated.Status = statusPublished
+			updated.PublishAt = nil
+			nextVersion(&updated, "", now)
+			m.blogs[id] = updated
+			m.revisions[id] = append(m.revisions[id], newRevision(&updated))
+			published = append(published, &updated)
+		}
+	}
+	return published, nil
+}
+
+// isDue tells if the scheduled publication of a blog should happen by now
+func isDue(data *BlogItem, now time.Time) bool {
+	return data.DeletedAt == nil && data.status() == statusInReview && data.PublishAt != nil && !data.PublishAt.After(now)
+}
+
+func (m *memoryStore) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var purged int64
+	for id, data := range m.blogs {
+		if data.DeletedAt != nil && data.DeletedAt.Before(before) {
+			delete(m.blogs, id)
+			delete(m.revisions, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (m *memoryStore) ListRevisions(ctx context.Context, id primitive.ObjectID, fn func(*RevisionItem) error) error {
+	// revisions are never modified, only appended, so the slice can be walked without the lock
+	m.mu.RLock()
+	revisions := m.revisions[id]
+	m.mu.RUnlock()
+
+	for i := range revisions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rev := revisions[i]
+		if err := fn(&rev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) GetRevision(ctx context.Context, id primitive.ObjectID, revision int64) (*RevisionItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, rev := range m.revisions[id] {
+		if rev.Revision == revision {
+			return &rev, nil
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
+
+func (m *memoryStore) GetRevisionAt(ctx context.Context, id primitive.ObjectID, asOf time.Time) (*RevisionItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return revisionAt(m.revisions[id], asOf)
+}
+
+// revisionAt picks the last revision made at or before asOf, revisions must be oldest first
+func revisionAt(revisions []RevisionItem, asOf time.Time) (*RevisionItem, error) {
+	var found *RevisionItem
+	for i := range revisions {
+		if revisions[i].CreatedAt.After(asOf) {
+			break
+		}
+		rev := revisions[i]
+		found = &rev
+	}
+	if found == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return found, nil
+}
+
+func (m *memoryStore) List(ctx context.Context, q ListQuery, fn func(*BlogItem) error) error {
+	// take a snapshot of the matching blogs, so fn (usually a stream.Send) runs without holding the lock
+	m.mu.RLock()
+	matches := make([]BlogItem, 0, len(m.blogs))
+	for _, data := range m.blogs {
+		if matchesQuery(&data, q) {
+			matches = append(matches, data)
+		}
+	}
+	m.mu.RUnlock()
+
+	return sendItems(ctx, matches, q, fn)
+}
+
+func (m *memoryStore) Search(ctx context.Context, q SearchQuery, fn func(item *BlogItem, score float64) error) error {
+	ids, scores, err := m.index.search(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		// the blog may have been deleted since the index was searched
+		data, err := m.Get(ctx, id)
+		if err == ErrBlogNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(data, scores[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendItems sorts the blogs already filtered with matchesQuery and hands them to fn,
+// honoring the limit of q and the cancellation of ctx
+func sendItems(ctx context.Context, items []BlogItem, q ListQuery, fn func(*BlogItem) error) error {
+	sortItems(items, q)
+
+	for i := range items {
+		if q.Limit > 0 && int64(i) >= q.Limit {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchesQuery applies the filters and the cursor of q to a single blog,
+// it is shared by the stores that can't push the query down to a database
+func matchesQuery(data *BlogItem, q ListQuery) bool {
+	if q.Deleted != (data.DeletedAt != nil) {
+		return false
+	}
+	if q.Status != "" && data.status() != q.Status {
+		return false
+	}
+	if q.AuthorID != "" && data.AuthorID != q.AuthorID {
+		return false
+	}
+	if !strings.HasPrefix(data.Title, q.TitlePrefix) {
+		return false
+	}
+	if q.After != nil {
+		c := compareItem(data, q.SortBy, q.After.Value, q.After.ID)
+		if (!q.Desc && c <= 0) || (q.Desc && c >= 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareItem orders data against the (sort value, id) pair of another blog, like the Mongo sort does
+func compareItem(data *BlogItem, field blogpb.ListBlogReq_SortField, value, id string) int {
+	if c := strings.Compare(sortValue(field, data), value); c != 0 {
+		return c
+	}
+	// hex ids have a fixed length, so comparing the strings compares the ObjectIds
+	return strings.Compare(data.ID.Hex(), id)
+}
+
+// sortItems sorts blogs in the order asked by q
+func sortItems(items []BlogItem, q ListQuery) {
+	sort.Slice(items, func(i, j int) bool {
+		c := compareItem(&items[i], q.SortBy, sortValue(q.SortBy, &items[j]), items[j].ID.Hex())
+		if q.Desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+func (m *memoryStore) CreateAPIKey(ctx context.Context, item *APIKeyItem) (*APIKeyItem, error) {
+	created := *item
+	created.ID = primitive.NewObjectID()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.apiKeys[created.ID] = created
+	m.apiKeyHashes[created.Hash] = created.ID
+	return &created, nil
+}
+
+func (m *memoryStore) GetAPIKey(ctx context.Context, id primitive.ObjectID) (*APIKeyItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key, ok := m.apiKeys[id]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+func (m *memoryStore) FindAPIKey(ctx context.Context, hash string) (*APIKeyItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.apiKeyHashes[hash]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	key := m.apiKeys[id]
+	return &key, nil
+}
+
+func (m *memoryStore) ListAPIKeys(ctx context.Context, tenant, ownerID string, fn func(*APIKeyItem) error) error {
+	m.mu.RLock()
+	var keys []APIKeyItem
+	for _, key := range m.apiKeys {
+		if key.Tenant == tenant && key.OwnerID == ownerID {
+			keys = append(keys, key)
+		}
+	}
+	m.mu.RUnlock()
+
+	// ObjectIds start with their creation time
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].ID.Hex() < keys[j].ID.Hex()
+	})
+	for i := range keys {
+		if err := fn(&keys[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memoryStore) RevokeAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) (*APIKeyItem, error) {
+	return m.changeAPIKey(id, func(key *APIKeyItem) {
+		key.RevokedAt = &at
+	})
+}
+
+func (m *memoryStore) RotateAPIKey(ctx context.Context, id primitive.ObjectID, hash, prefix string, at time.Time) (*APIKeyItem, error) {
+	return m.changeAPIKey(id, func(key *APIKeyItem) {
+		delete(m.apiKeyHashes, key.Hash)
+		m.apiKeyHashes[hash] = id
+		key.Hash, key.Prefix, key.RotatedAt = hash, prefix, &at
+	})
+}
+
+// changeAPIKey applies change to a key that isn't revoked, under m.mu
+func (m *memoryStore) changeAPIKey(id primitive.ObjectID, change func(key *APIKeyItem)) (*APIKeyItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key, ok := m.apiKeys[id]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	change(&key)
+	m.apiKeys[id] = key
+	return &key, nil
+}
@@ -0,0 +1,747 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoStore keeps the blogs as documents of a MongoDB collection, their revisions in a second one and the API keys in a third
+type mongoStore struct {
+	blogdb     *mongo.Collection
+	revisionDB *mongo.Collection
+	apiKeyDB   *mongo.Collection
+	// tenant is set when several tenants share the collections, every blog and revision then has a tenant field
+	// and the store only ever reads and writes the documents of its own tenant
+	tenant string
+}
+
+func newMongoStore(blogs, revisions, apiKeys *mongo.Collection) *mongoStore {
+	return &mongoStore{blogdb: blogs, revisionDB: revisions, apiKeyDB: apiKeys}
+}
+
+// withTenant returns a store of the blogs of a tenant, in the same collections as m
+func (m *mongoStore) withTenant(tenant string) *mongoStore {
+	scoped := *m
+	scoped.tenant = tenant
+	return &scoped
+}
+
+// newMongoTenants gives every tenant a store next to base, the way mode says:
+// in the collections of base, in collections prefixed with its name (acme.blog), or in its own database (mydb_acme).
+// The API keys stay in the collection of base in every mode.
+func newMongoTenants(base *mongoStore, mode string) *tenantStores {
+	database := base.blogdb.Database()
+	client := database.Client()
+
+	switch mode {
+	case "collection":
+		suffix := "." + base.blogdb.Name()
+		return newTenantStores(func(ctx context.Context, tenant string) (BlogStore, error) {
+			store := newMongoStore(database.Collection(tenant+suffix), database.Collection(tenant+"."+base.revisionDB.Name()), base.apiKeyDB)
+			return store, store.ensureIndexes(ctx)
+		}, func(ctx context.Context) ([]string, error) {
+			names, err := database.ListCollectionNames(ctx, bson.M{"name": bson.M{"$regex": regexp.QuoteMeta(suffix) + "$"}})
+			for i, name := range names {
+				names[i] = strings.TrimSuffix(name, suffix)
+			}
+			return names, err
+		})
+	case "database":
+		prefix := database.Name() + "_"
+		return newTenantStores(func(ctx context.Context, tenant string) (BlogStore, error) {
+			tenantDB := client.Database(prefix + tenant)
+			store := newMongoStore(tenantDB.Collection(base.blogdb.Name()), tenantDB.Collection(base.revisionDB.Name()), base.apiKeyDB)
+			return store, store.ensureIndexes(ctx)
+		}, func(ctx context.Context) ([]string, error) {
+			names, err := client.ListDatabaseNames(ctx, bson.M{"name": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)}})
+			for i, name := range names {
+				names[i] = strings.TrimPrefix(name, prefix)
+			}
+			return names, err
+		})
+	default:
+		// the tenants share the collections of base, ensureIndexes adds the indexes leading with the tenant
+		return newTenantStores(func(ctx context.Context, tenant string) (BlogStore, error) {
+			store := base.withTenant(tenant)
+			return store, store.ensureIndexes(ctx)
+		}, func(ctx context.Context) ([]string, error) {
+			values, err := base.blogdb.Distinct(ctx, "tenant", bson.M{})
+			var names []string
+			for _, v := range values {
+				if name, ok := v.(string); ok {
+					names = append(names, name)
+				}
+			}
+			return names, err
+		})
+	}
+}
+
+// scope restricts filter to the documents of the tenant of the store, every blog and revision query goes through it
+func (m *mongoStore) scope(filter bson.M) bson.M {
+	if m.tenant != "" {
+		filter["tenant"] = m.tenant
+	}
+	return filter
+}
+
+// ensureIndexes creates the indexes the queries of the store rely on, it is a no-op if they already exist
+func (m *mongoStore) ensureIndexes(ctx context.Context) error {
+	_, err := m.revisionDB.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "blog_id", Value: 1}, {Key: "revision", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// the scheduler looks for due publications on every tick, only scheduled blogs have the field
+	_, err = m.blogdb.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "publish_at", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	// full-text search, a collection can only have one text index so it covers both fields
+	_, err = m.blogdb.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "content", Value: "text"}},
+		Options: options.Index().SetName("blog_text"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// the queries of a tenant sharing the collections always filter on it first
+	if m.tenant != "" {
+		_, err = m.blogdb.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "tenant", Value: 1}, {Key: "_id", Value: 1}}})
+		if err != nil {
+			return err
+		}
+		_, err = m.revisionDB.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "tenant", Value: 1}, {Key: "blog_id", Value: 1}, {Key: "revision", Value: 1}}})
+		if err != nil {
+			return err
+		}
+	}
+
+	// every call made with an API key looks it up by hash, and users list their own keys
+	_, err = m.apiKeyDB.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tenant", Value: 1}, {Key: "owner_id", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
+
+func (m *mongoStore) Create(ctx context.Context, item *BlogItem) (*BlogItem, error) {
+	created := *item
+	created.Revision = 1
+	created.Tenant = m.tenant
+
+	// ID is empty, so it gets omitted and MongoDB generates a unique Object ID upon insertion.
+	result, err := m.blogdb.InsertOne(ctx, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	// first cast the "generic type" to an Object ID
+	created.ID = result.InsertedID.(primitive.ObjectID)
+
+	if _, err := m.revisionDB.InsertOne(ctx, newRevision(&created)); err != nil {
+		return nil, fmt.Errorf("blog %s created but its revision was not recorded: %w", created.ID.Hex(), err)
+	}
+	return &created, nil
+}
+
+// The MongoDB FindOne() methods takes in a context and a filter, which is a BSON document for which to filter by its keys
+func (m *mongoStore) Get(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	data := &BlogItem{}
+	err := m.blogdb.FindOne(ctx, m.liveFilter(id)).Decode(data)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrBlogNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (m *mongoStore) Update(ctx context.Context, item *BlogItem, fields []string, expectedVersion int64) (*BlogItem, error) {
+	// convert the fields to be updated into an unordered Bson document, the others are left untouched
+	set := bson.M{
+		"updated_by": item.UpdatedBy,
+		"updated_at": item.UpdatedAt,
+	}
+	values := bson.M{"author_id": item.AuthorID, "title": item.Title, "content": item.Content}
+	for _, field := range fields {
+		set[field] = values[field]
+	}
+
+	update := bson.M{
+		"$set": set,
+		// the increment is atomic, so two concurrent updates can't record the same revision
+		"$inc": bson.M{"revision": 1},
+	}
+
+	// matching on the revision makes the version check and the write a single atomic operation
+	filter := m.liveFilter(item.ID)
+	if expectedVersion != 0 {
+		filter["revision"] = expectedVersion
+	}
+
+	// the original document is returned, it is needed to snapshot blogs created before revisions existed
+	// and to rebuild the full updated blog from a partial update
+	result := m.blogdb.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.Before))
+
+	previous := &BlogItem{}
+	err := result.Decode(previous)
+	if err == mongo.ErrNoDocuments {
+		return nil, m.missError(ctx, item.ID, expectedVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *previous
+	applyFields(&updated, item, fields)
+	updated.Revision = previous.Revision + 1
+	if err := m.recordRevision(ctx, previous, &updated); err != nil {
+		return nil, fmt.Errorf("blog %s updated but its revision was not recorded: %w", item.ID.Hex(), err)
+	}
+	return &updated, nil
+}
+
+// recordRevision records the revision of updated, written over previous.
+// Without a replica set there are no transactions, the blog is written first and its revision right after.
+func (m *mongoStore) recordRevision(ctx context.Context, previous, updated *BlogItem) error {
+	// a blog without revision predates the history, keep its original fields as revision 0
+	revisions := []interface{}{}
+	if previous.Revision == 0 {
+		original := *previous
+		original.UpdatedBy = previous.AuthorID
+		original.UpdatedAt = previous.ID.Timestamp()
+		revisions = append(revisions, newRevision(&original))
+	}
+	revisions = append(revisions, newRevision(updated))
+
+	_, err := m.revisionDB.InsertMany(ctx, revisions)
+	return err
+}
+
+// versionUpdate adds to update, which has a $set, the fields of the next version of a blog changed by editor at the given time.
+// The increment is atomic, so two concurrent changes can't record the same revision.
+func versionUpdate(update bson.M, editor string, at time.Time) bson.M {
+	set := update["$set"].(bson.M)
+	set["updated_at"] = at
+	if editor != "" {
+		set["updated_by"] = editor
+	}
+	update["$inc"] = bson.M{"revision": 1}
+	return update
+}
+
+func (m *mongoStore) Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64, at time.Time) error {
+	filter := m.liveFilter(id)
+	if expectedVersion != 0 {
+		filter["revision"] = expectedVersion
+	}
+
+	result, err := m.blogdb.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"deleted_at": at}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return m.missError(ctx, id, expectedVersion)
+	}
+	return nil
+}
+
+func (m *mongoStore) Undelete(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	filter := m.scope(bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}})
+	result := m.blogdb.FindOneAndUpdate(ctx, filter, bson.M{"$unset": bson.M{"deleted_at": ""}}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	data := &BlogItem{}
+	err := result.Decode(data)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrBlogNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (m *mongoStore) GetDeleted(ctx context.Context, id primitive.ObjectID) (*BlogItem, error) {
+	data := &BlogItem{}
+	err := m.blogdb.FindOne(ctx, m.scope(bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}})).Decode(data)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrBlogNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (m *mongoStore) Purge(ctx context.Context, id primitive.ObjectID) error {
+	result, err := m.blogdb.DeleteOne(ctx, m.scope(bson.M{"_id": id, "deleted_at": bson.M{"$ne": nil}}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrBlogNotFound
+	}
+
+	_, err = m.revisionDB.DeleteMany(ctx, m.scope(bson.M{"blog_id": id}))
+	return err
+}
+
+func (m *mongoStore) SetStatus(ctx context.Context, id primitive.ObjectID, from, to, editor string, at time.Time) (*BlogItem, error) {
+	// the status is part of the filter, so checking it and changing it, along with the version, is one atomic operation
+	filter := m.liveFilter(id)
+	filter["status"] = statusCondition(from)
+	update := versionUpdate(bson.M{"$set": bson.M{"status": to}, "$unset": bson.M{"publish_at": ""}}, editor, at)
+	// the original document is returned, like for Update
+	result := m.blogdb.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.Before))
+
+	previous := &BlogItem{}
+	err := result.Decode(previous)
+	if err == mongo.ErrNoDocuments {
+		// tell a missing blog from one in another status
+		current, err := m.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &StatusError{ID: id, Current: current.status(), To: to}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *previous
+	updated.Status = to
+	updated.PublishAt = nil
+	nextVersion(&updated, editor, at)
+	if err := m.recordRevision(ctx, previous, &updated); err != nil {
+		return nil, fmt.Errorf("blog %s moved to %s but its revision was not recorded: %w", id.Hex(), to, err)
+	}
+	return &updated, nil
+}
+
+func (m *mongoStore) SchedulePublish(ctx context.Context, id primitive.ObjectID, publishAt time.Time, editor string, at time.Time) (*BlogItem, error) {
+	filter := m.liveFilter(id)
+	filter["status"] = statusInReview
+	update := versionUpdate(bson.M{"$set": bson.M{"publish_at": publishAt}}, editor, at)
+	result := m.blogdb.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.Before))
+
+	previous := &BlogItem{}
+	err := result.Decode(previous)
+	if err == mongo.ErrNoDocuments {
+		current, err := m.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &StatusError{ID: id, Current: current.status(), To: statusPublished}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *previous
+	updated.PublishAt = &publishAt
+	nextVersion(&updated, editor, at)
+	if err := m.recordRevision(ctx, previous, &updated); err != nil {
+		return nil, fmt.Errorf("blog %s scheduled but its revision was not recorded: %w", id.Hex(), err)
+	}
+	return &updated, nil
+}
+
+func (m *mongoStore) PublishDue(ctx context.Context, now time.Time) ([]*BlogItem, error) {
+	filter := m.scope(bson.M{
+		"deleted_at": nil,
+		"status":     statusInReview,
+		"publish_at": bson.M{"$lte": now},
+	})
+	update := versionUpdate(bson.M{"$set": bson.M{"status": statusPublished}, "$unset": bson.M{"publish_at": ""}}, "", now)
+
+	// claim the due blogs one by one, every FindOneAndUpdate is atomic,
+	// so when several servers run the scheduler each blog is published by exactly one of them
+	var published []*BlogItem
+	for {
+		previous := &BlogItem{}
+		err := m.blogdb.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.Before)).Decode(previous)
+		if err == mongo.ErrNoDocuments {
+			return published, nil
+		}
+		if err != nil {
+			return published, err
+		}
+
+		updated := *previous
+		updated.Status = statusPublished
+		updated.PublishAt = nil
+		nextVersion(&updated, "", now)
+		published = append(published, &updated)
+		if err := m.recordRevision(ctx, previous, &updated); err != nil {
+			return published, fmt.Errorf("blog %s published but its revision was not recorded: %w", updated.ID.Hex(), err)
+		}
+	}
+}
+
+func (m *mongoStore) PurgeDeletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	// the revisions live in another collection, so the ids of the blogs to purge are needed first
+	filter := m.scope(bson.M{"deleted_at": bson.M{"$lt": before}})
+	cursor, err := m.blogdb.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, err
+	}
+	var expired []BlogItem
+	if err := cursor.All(ctx, &expired); err != nil {
+		return 0, err
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	ids := bson.A{}
+	for _, data := range expired {
+		ids = append(ids, data.ID)
+	}
+
+	// the deleted_at condition is repeated, a blog undeleted in the meantime must survive
+	result, err := m.blogdb.DeleteMany(ctx, m.scope(bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$lt": before}}))
+	if err != nil {
+		return 0, err
+	}
+
+	// only drop the revisions of the blogs that are really gone
+	survivors, err := m.blogdb.Distinct(ctx, "_id", m.scope(bson.M{"_id": bson.M{"$in": ids}}))
+	if err != nil {
+		return result.DeletedCount, err
+	}
+	purged := bson.A{}
+	for _, id := range ids {
+		if !containsID(survivors, id) {
+			purged = append(purged, id)
+		}
+	}
+
+	_, err = m.revisionDB.DeleteMany(ctx, m.scope(bson.M{"blog_id": bson.M{"$in": purged}}))
+	return result.DeletedCount, err
+}
+
+func containsID(ids []interface{}, id interface{}) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// missError tells why a write filtered on id and expectedVersion matched nothing:
+// either the blog doesn't exist or it is at another version
+func (m *mongoStore) missError(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	current, err := m.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(expectedVersion, current); err != nil {
+		return err
+	}
+	// the blog changed again between the write and the read, report the version it was seen at
+	return &VersionMismatchError{ID: id, Expected: expectedVersion, Current: current.Revision}
+}
+
+func (m *mongoStore) List(ctx context.Context, q ListQuery, fn func(*BlogItem) error) error {
+	// all conditions are ANDed, the cursor and the title prefix may both target the title field
+	conditions := bson.A{}
+	if q.Deleted {
+		conditions = append(conditions, bson.M{"deleted_at": bson.M{"$ne": nil}})
+	} else {
+		conditions = append(conditions, bson.M{"deleted_at": nil})
+	}
+	if q.Status != "" {
+		conditions = append(conditions, bson.M{"status": statusCondition(q.Status)})
+	}
+	if q.AuthorID != "" {
+		conditions = append(conditions, bson.M{"author_id": q.AuthorID})
+	}
+	if q.TitlePrefix != "" {
+		conditions = append(conditions, bson.M{"title": bson.M{"$regex": "^" + regexp.QuoteMeta(q.TitlePrefix)}})
+	}
+
+	sortKey := mongoSortKey(q.SortBy)
+	order, op := 1, "$gt"
+	if q.Desc {
+		order, op = -1, "$lt"
+	}
+
+	// continue strictly after the last blog sent, ties on the sort field are broken by id
+	if q.After != nil {
+		lastID, err := primitive.ObjectIDFromHex(q.After.ID)
+		if err != nil {
+			return fmt.Errorf("malformed cursor id: %w", err)
+		}
+		if sortKey == "_id" {
+			conditions = append(conditions, bson.M{"_id": bson.M{op: lastID}})
+		} else {
+			conditions = append(conditions, bson.M{"$or": bson.A{
+				bson.M{sortKey: bson.M{op: q.After.Value}},
+				bson.M{sortKey: q.After.Value, "_id": bson.M{op: lastID}},
+			}})
+		}
+	}
+
+	filter := m.scope(bson.M{"$and": conditions})
+
+	sort := bson.D{{Key: sortKey, Value: order}}
+	if sortKey != "_id" {
+		sort = append(sort, bson.E{Key: "_id", Value: order})
+	}
+	findOptions := options.Find().SetSort(sort)
+	if q.Limit > 0 {
+		findOptions.SetLimit(q.Limit)
+	}
+
+	// collection.Find returns a cursor for our query
+	cursor, err := m.blogdb.Find(ctx, filter, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	// cursor.Next() returns a boolean, if false there are no more items (or ctx is done) and loop will break
+	for cursor.Next(ctx) {
+		data := &BlogItem{}
+		if err := cursor.Decode(data); err != nil {
+			return fmt.Errorf("could not decode data: %w", err)
+		}
+		if err := fn(data); err != nil {
+			return err
+		}
+	}
+
+	// a cancelled ctx surfaces as a cursor error, report it as what it is
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return cursor.Err()
+}
+
+func (m *mongoStore) Search(ctx context.Context, q SearchQuery, fn func(item *BlogItem, score float64) error) error {
+	// rebuild the $search string from the parsed query, so the syntax is the same whatever the store
+	terms := append([]string{}, q.Words...)
+	for _, phrase := range q.Phrases {
+		terms = append(terms, `"`+phrase+`"`)
+	}
+
+	filter := m.scope(bson.M{
+		"$text":      bson.M{"$search": strings.Join(terms, " ")},
+		"deleted_at": nil,
+	})
+	if q.AuthorID != "" {
+		filter["author_id"] = q.AuthorID
+	}
+
+	score := bson.M{"score": bson.M{"$meta": "textScore"}}
+	findOptions := options.Find().SetProjection(score).SetSort(score).SetLimit(int64(q.Limit))
+
+	cursor, err := m.blogdb.Find(ctx, filter, findOptions)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		hit := struct {
+			BlogItem `bson:",inline"`
+			Score    float64 `bson:"score"`
+		}{}
+		if err := cursor.Decode(&hit); err != nil {
+			return fmt.Errorf("could not decode data: %w", err)
+		}
+		if err := fn(&hit.BlogItem, hit.Score); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return cursor.Err()
+}
+
+func (m *mongoStore) ListRevisions(ctx context.Context, id primitive.ObjectID, fn func(*RevisionItem) error) error {
+	cursor, err := m.revisionDB.Find(ctx, m.scope(bson.M{"blog_id": id}), options.Find().SetSort(bson.D{{Key: "revision", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		rev := &RevisionItem{}
+		if err := cursor.Decode(rev); err != nil {
+			return fmt.Errorf("could not decode revision: %w", err)
+		}
+		if err := fn(rev); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return cursor.Err()
+}
+
+func (m *mongoStore) GetRevision(ctx context.Context, id primitive.ObjectID, revision int64) (*RevisionItem, error) {
+	return m.findRevision(ctx, bson.M{"blog_id": id, "revision": revision}, nil)
+}
+
+func (m *mongoStore) GetRevisionAt(ctx context.Context, id primitive.ObjectID, asOf time.Time) (*RevisionItem, error) {
+	// the latest revision that isn't newer than asOf
+	filter := bson.M{"blog_id": id, "created_at": bson.M{"$lte": asOf}}
+	return m.findRevision(ctx, filter, options.FindOne().SetSort(bson.D{{Key: "revision", Value: -1}}))
+}
+
+func (m *mongoStore) findRevision(ctx context.Context, filter bson.M, opts *options.FindOneOptions) (*RevisionItem, error) {
+	if opts == nil {
+		opts = options.FindOne()
+	}
+
+	rev := &RevisionItem{}
+	err := m.revisionDB.FindOne(ctx, m.scope(filter), opts).Decode(rev)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// liveFilter matches the blog with this id unless it is in the trash,
+// a null deleted_at also matches the documents written before soft deletes existed
+func (m *mongoStore) liveFilter(id primitive.ObjectID) bson.M {
+	return m.scope(bson.M{"_id": id, "deleted_at": nil})
+}
+
+// tenantCondition matches the documents of a tenant, the ones without tenant field for a blank tenant
+func tenantCondition(tenant string) interface{} {
+	if tenant == "" {
+		return nil
+	}
+	return tenant
+}
+
+// statusCondition matches the documents in the given status, see BlogItem.status()
+func statusCondition(status string) interface{} {
+	if status == statusPublished {
+		return bson.M{"$in": bson.A{statusPublished, nil}}
+	}
+	return status
+}
+
+// mongoSortKey maps the requested sort field to its BSON key
+func mongoSortKey(field blogpb.ListBlogReq_SortField) string {
+	switch field {
+	case blogpb.ListBlogReq_TITLE:
+		return "title"
+	case blogpb.ListBlogReq_AUTHOR_ID:
+		return "author_id"
+	default:
+		return "_id"
+	}
+}
+
+func (m *mongoStore) CreateAPIKey(ctx context.Context, item *APIKeyItem) (*APIKeyItem, error) {
+	created := *item
+	result, err := m.apiKeyDB.InsertOne(ctx, &created)
+	if err != nil {
+		return nil, err
+	}
+	created.ID = result.InsertedID.(primitive.ObjectID)
+	return &created, nil
+}
+
+func (m *mongoStore) GetAPIKey(ctx context.Context, id primitive.ObjectID) (*APIKeyItem, error) {
+	return m.findAPIKey(ctx, bson.M{"_id": id})
+}
+
+func (m *mongoStore) FindAPIKey(ctx context.Context, hash string) (*APIKeyItem, error) {
+	return m.findAPIKey(ctx, bson.M{"hash": hash})
+}
+
+func (m *mongoStore) findAPIKey(ctx context.Context, filter bson.M) (*APIKeyItem, error) {
+	key := &APIKeyItem{}
+	err := m.apiKeyDB.FindOne(ctx, filter).Decode(key)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (m *mongoStore) ListAPIKeys(ctx context.Context, tenant, ownerID string, fn func(*APIKeyItem) error) error {
+	// a blank tenant matches the keys without one, like the ones created before tenancy was enabled
+	cursor, err := m.apiKeyDB.Find(ctx, bson.M{"tenant": tenantCondition(tenant), "owner_id": ownerID}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		key := &APIKeyItem{}
+		if err := cursor.Decode(key); err != nil {
+			return fmt.Errorf("could not decode api key: %w", err)
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return cursor.Err()
+}
+
+func (m *mongoStore) RevokeAPIKey(ctx context.Context, id primitive.ObjectID, at time.Time) (*APIKeyItem, error) {
+	return m.changeAPIKey(ctx, id, bson.M{"revoked_at": at})
+}
+
+func (m *mongoStore) RotateAPIKey(ctx context.Context, id primitive.ObjectID, hash, prefix string, at time.Time) (*APIKeyItem, error) {
+	return m.changeAPIKey(ctx, id, bson.M{"hash": hash, "prefix": prefix, "rotated_at": at})
+}
+
+// changeAPIKey sets fields on a key that isn't revoked, atomically
+func (m *mongoStore) changeAPIKey(ctx context.Context, id primitive.ObjectID, set bson.M) (*APIKeyItem, error) {
+	filter := bson.M{"_id": id, "revoked_at": nil}
+	result := m.apiKeyDB.FindOneAndUpdate(ctx, filter, bson.M{"$set": set}, options.FindOneAndUpdate().SetReturnDocument(options.After))
+
+	key := &APIKeyItem{}
+	err := result.Decode(key)
+	if err == mongo.ErrNoDocuments {
+		// either there is no such key or it is revoked
+		if _, err := m.GetAPIKey(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrAPIKeyRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,569 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testStores are the stores running without a database, every contract test runs against each of them
+var testStores = []struct {
+	name string
+	open func(t *testing.T) BlogStore
+}{
+	{"memory", func(t *testing.T) BlogStore {
+		store, err := newMemoryStore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return store
+	}},
+	{"bolt", func(t *testing.T) BlogStore {
+		store, err := newBoltStore(filepath.Join(t.TempDir(), "blog.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	}},
+}
+
+// forEachStore runs test against a new empty store of every kind
+func forEachStore(t *testing.T, test func(t *testing.T, store BlogStore)) {
+	for _, s := range testStores {
+		s := s
+		t.Run(s.name, func(t *testing.T) {
+			test(t, s.open(t))
+		})
+	}
+}
+
+// testTime is when the changes of the tests happen, truncated like now() does
+var testTime = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func createBlog(t *testing.T, store BlogStore, author, title string) *BlogItem {
+	t.Helper()
+	created, err := store.Create(context.Background(), &BlogItem{
+		AuthorID:  author,
+		Title:     title,
+		Content:   "content of " + title,
+		UpdatedBy: author,
+		UpdatedAt: testTime,
+		Status:    statusDraft,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return created
+}
+
+// revisionNumbers lists the revisions recorded for a blog, oldest first
+func revisionNumbers(t *testing.T, store BlogStore, id primitive.ObjectID) []int64 {
+	t.Helper()
+	var numbers []int64
+	err := store.ListRevisions(context.Background(), id, func(rev *RevisionItem) error {
+		numbers = append(numbers, rev.Revision)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	return numbers
+}
+
+func equalNumbers(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStoreCreateGet(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store BlogStore) {
+		ctx := context.Background()
+		created := createBlog(t, store, "alice", "first")
+		if created.ID.IsZero() || created.Revision != 1 {
+			t.Fatalf("Create returned id %s at version %d, want a new id at version 1", created.ID.Hex(), created.Revision)
+		}
+
+		got, err := store.Get(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.Title != "first" || got.AuthorID != "alice" || got.Revision != 1 || got.status() != statusDraft {
+			t.Errorf("Get returned %+v", got)
+		}
+		if _, err := store.Get(ctx, primitive.NewObjectID()); err != ErrBlogNotFound {
+			t.Errorf("Get of a missing blog returned %v, want ErrBlogNotFound", err)
+		}
+
+		rev, err := store.GetRevision(ctx, created.ID, 1)
+		if err != nil {
+			t.Fatalf("GetRevision: %v", err)
+		}
+		if rev.Title != "first" || rev.EditorID != "alice" || !rev.CreatedAt.Equal(testTime) {
+			t.Errorf("first revision is %+v", rev)
+		}
+	})
+}
+
+func TestStoreUpdate(t *testing.T) {
+	tests := []struct {
+		name            string
+		fields          []string
+		expectedVersion int64
+		wantErr         error
+		wantTitle       string
+		wantContent     string
+	}{
+		{name: "every field", fields: blogFields, wantTitle: "changed", wantContent: "changed content"},
+		{name: "only the title", fields: []string{"title"}, wantTitle: "changed", wantContent: "content of first"},
+		{name: "at the expected version", fields: blogFields, expectedVersion: 1, wantTitle: "changed", wantContent: "changed content"},
+		{name: "at another version", fields: blogFields, expectedVersion: 2, wantErr: &VersionMismatchError{Expected: 2, Current: 1}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			forEachStore(t, func(t *testing.T, store BlogStore) {
+				ctx := context.Background()
+				created := createBlog(t, store, "alice", "first")
+
+				updated, err := store.Update(ctx, &BlogItem{
+					ID:        created.ID,
+					AuthorID:  "alice",
+					Title:     "changed",
+					Content:   "changed content",
+					UpdatedBy: "bob",
+					UpdatedAt: testTime.Add(time.Minute),
+				}, tt.fields, tt.expectedVersion)
+
+				if tt.wantErr != nil {
+					var mismatch *VersionMismatchError
+					if !errors.As(err, &mismatch) || mismatch.Current != 1 || mismatch.Expected != tt.expectedVersion {
+						t.Fatalf("Update returned %v, want %v", err, tt.wantErr)
+					}
+					if got := revisionNumbers(t, store, created.ID); !equalNumbers(got, []int64{1}) {
+						t.Errorf("revisions are %v after a rejected update, want [1]", got)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Update: %v", err)
+				}
+				if updated.Revision != 2 || updated.Title != tt.wantTitle || updated.Content != tt.wantContent {
+					t.Errorf("Update returned %+v", updated)
+				}
+
+				got, err := store.Get(ctx, created.ID)
+				if err != nil {
+					t.Fatalf("Get: %v", err)
+				}
+				if got.Revision != 2 || got.Title != tt.wantTitle || got.Content != tt.wantContent {
+					t.Errorf("Get after Update returned %+v", got)
+				}
+
+				rev, err := store.GetRevision(ctx, created.ID, 2)
+				if err != nil {
+					t.Fatalf("GetRevision: %v", err)
+				}
+				if rev.Title != tt.wantTitle || rev.EditorID != "bob" {
+					t.Errorf("revision 2 is %+v", rev)
+				}
+			})
+		})
+	}
+}
+
+func TestStoreUpdateMissing(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store BlogStore) {
+		_, err := store.Update(context.Background(), &BlogItem{ID: primitive.NewObjectID(), Title: "x"}, blogFields, 0)
+		if err != ErrBlogNotFound {
+			t.Errorf("Update of a missing blog returned %v, want ErrBlogNotFound", err)
+		}
+	})
+}
+
+func TestStoreRevisionAt(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store BlogStore) {
+		ctx := context.Background()
+		created := createBlog(t, store, "alice", "first")
+		_, err := store.Update(ctx, &BlogItem{ID: created.ID, Title: "second", UpdatedBy: "alice", UpdatedAt: testTime.Add(time.Hour)}, []string{"title"}, 0)
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		tests := []struct {
+			asOf time.Time
+			want int64 // 0 for ErrRevisionNotFound
+		}{
+			{testTime.Add(-time.Second), 0},
+			{testTime, 1},
+			{testTime.Add(time.Minute), 1},
+			{testTime.Add(time.Hour), 2},
+			{testTime.Add(24 * time.Hour), 2},
+		}
+		for _, tt := range tests {
+			rev, err := store.GetRevisionAt(ctx, created.ID, tt.asOf)
+			if tt.want == 0 {
+				if err != ErrRevisionNotFound {
+					t.Errorf("GetRevisionAt(%v) returned %v, want ErrRevisionNotFound", tt.asOf, err)
+				}
+				continue
+			}
+			if err != nil || rev.Revision != tt.want {
+				t.Errorf("GetRevisionAt(%v) returned %+v, %v, want revision %d", tt.asOf, rev, err, tt.want)
+			}
+		}
+
+		if _, err := store.GetRevision(ctx, created.ID, 3); err != ErrRevisionNotFound {
+			t.Errorf("GetRevision of a missing revision returned %v, want ErrRevisionNotFound", err)
+		}
+	})
+}
+
+func TestStoreTrash(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store BlogStore) {
+		ctx := context.Background()
+		created := createBlog(t, store, "alice", "first")
+
+		var mismatch *VersionMismatchError
+		if err := store.Delete(ctx, created.ID, 5, testTime); !errors.As(err, &mismatch) {
+			t.Fatalf("Delete at another version returned %v, want a *VersionMismatchError", err)
+		}
+		if err := store.Purge(ctx, created.ID); err != ErrBlogNotFound {
+			t.Errorf("Purge of a live blog returned %v, want ErrBlogNotFound", err)
+		}
+		if err := store.Delete(ctx, created.ID, 1, testTime); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		// in the trash, the blog is missing for everything but the trash methods
+		if _, err := store.Get(ctx, created.ID); err != ErrBlogNotFound {
+			t.Errorf("Get of a deleted blog returned %v, want ErrBlogNotFound", err)
+		}
+		if err := store.Delete(ctx, created.ID, 0, testTime); err != ErrBlogNotFound {
+			t.Errorf("Delete of a deleted blog returned %v, want ErrBlogNotFound", err)
+		}
+		if _, err := store.SetStatus(ctx, created.ID, statusDraft, statusInReview, "", testTime); err != ErrBlogNotFound {
+			t.Errorf("SetStatus of a deleted blog returned %v, want ErrBlogNotFound", err)
+		}
+		deleted, err := store.GetDeleted(ctx, created.ID)
+		if err != nil || deleted.DeletedAt == nil || !deleted.DeletedAt.Equal(testTime) {
+			t.Fatalf("GetDeleted returned %+v, %v", deleted, err)
+		}
+
+		undeleted, err := store.Undelete(ctx, created.ID)
+		if err != nil || undeleted.DeletedAt != nil {
+			t.Fatalf("Undelete returned %+v, %v", undeleted, err)
+		}
+		if _, err := store.Undelete(ctx, created.ID); err != ErrBlogNotFound {
+			t.Errorf("Undelete of a live blog returned %v, want ErrBlogNotFound", err)
+		}
+		if _, err := store.Get(ctx, created.ID); err != nil {
+			t.Errorf("Get of an undeleted blog returned %v", err)
+		}
+
+		if err := store.Delete(ctx, created.ID, 0, testTime); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if err := store.Purge(ctx, created.ID); err != nil {
+			t.Fatalf("Purge: %v", err)
+		}
+		if _, err := store.GetDeleted(ctx, created.ID); err != ErrBlogNotFound {
+			t.Errorf("GetDeleted of a purged blog returned %v, want ErrBlogNotFound", err)
+		}
+		if got := revisionNumbers(t, store, created.ID); len(got) != 0 {
+			t.Errorf("revisions %v are left after the purge", got)
+		}
+	})
+}
+
+func TestStorePurgeDeletedBefore(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store BlogStore) {
+		ctx := context.Background()
+		old := createBlog(t, store, "alice", "old")
+		recent := createBlog(t, store, "alice", "recent")
+		live := createBlog(t, store, "alice", "live")
+		if err := store.Delete(ctx, old.ID, 0, testTime); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Delete(ctx, recent.ID, 0, testTime.Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+
+		purged, err := store.PurgeDeletedBefore(ctx, testTime.Add(time.Minute))
+		if err != nil || purged != 1 {
+			t.Fatalf("PurgeDeletedBefore returned %d, %v, want 1", purged, err)
+		}
+		if _, err := store.GetDeleted(ctx, old.ID); err != ErrBlogNotFound {
+			t.Errorf("the blog deleted before is still in the trash: %v", err)
+		}
+		if _, err := store.GetDeleted(ctx, recent.ID); err != nil {
+			t.Errorf("the blog deleted after was purged: %v", err)
+		}
+		if _, err := store.Get(ctx, live.ID); err != nil {
+			t.Errorf("the live blog was purged: %v", err)
+		}
+	})
+}
+
+func TestStoreSetStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     []string // statuses the blog goes through after draft
+		from, to string
+		wantErr  bool
+	}{
+		{name: "submit a draft", from: statusDraft, to: statusInReview},
+		{name: "publish a draft", from: statusInReview, to: statusPublished, wantErr: true},
+		{name: "publish in review", path: []string{statusInReview}, from: statusInReview, to: statusPublished},
+		{name: "archive published", path: []string{statusInReview, statusPublished}, from: statusPublished, to: statusArchived},
+		{name: "submit twice", path: []string{statusInReview}, from: statusDraft, to: statusInReview, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			forEachStore(t, func(t *testing.T, store BlogStore) {
+				ctx := context.Background()
+				created := createBlog(t, store, "alice", "first")
+				current := statusDraft
+				for _, to := range tt.path {
+					if _, err := store.SetStatus(ctx, created.ID, current, to, "", testTime); err != nil {
+						t.Fatalf("SetStatus to %s: %v", to, err)
+					}
+					current = to
+				}
+
+				// every status change is a version of its own
+				version := int64(1 + len(tt.path))
+				data, err := store.SetStatus(ctx, created.ID, tt.from, tt.to, "bob", testTime.Add(time.Hour))
+				if tt.wantErr {
+					var statusErr *StatusError
+					if !errors.As(err, &statusErr) || statusErr.Current != current || statusErr.To != tt.to {
+						t.Fatalf("SetStatus returned %v, want a *StatusError from %s", err, current)
+					}
+					if got, err := store.Get(ctx, created.ID); err != nil || got.Revision != version {
+						t.Errorf("Get after a rejected SetStatus returned %+v, %v, want version %d", got, err, version)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("SetStatus: %v", err)
+				}
+				if data.status() != tt.to || data.Revision != version+1 {
+					t.Errorf("SetStatus returned a blog in %s at version %d, want %s at version %d", data.status(), data.Revision, tt.to, version+1)
+				}
+				got, err := store.Get(ctx, created.ID)
+				if err != nil || got.status() != tt.to || got.Revision != version+1 {
+					t.Errorf("Get after SetStatus returned %+v, %v", got, err)
+				}
+
+				rev, err := store.GetRevision(ctx, created.ID, version+1)
+				if err != nil {
+					t.Fatalf("GetRevision of the status change: %v", err)
+				}
+				if rev.EditorID != "bob" || !rev.CreatedAt.Equal(testTime.Add(time.Hour)) || rev.Title != "first" {
+					t.Errorf("revision of the status change is %+v", rev)
+				}
+
+				// writes expecting the version before the change are rejected
+				var mismatch *VersionMismatchError
+				_, err = store.Update(ctx, &BlogItem{ID: created.ID, Title: "late"}, []string{"title"}, version)
+				if !errors.As(err, &mismatch) || mismatch.Current != version+1 {
+					t.Errorf("Update at the version before the status change returned %v, want a *VersionMismatchError", err)
+				}
+			})
+		})
+	}
+}
+
+func TestStoreScheduledPublication(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store BlogStore) {
+		ctx := context.Background()
+		draft := createBlog(t, store, "alice", "draft")
+		due := createBlog(t, store, "alice", "due")
+		later := createBlog(t, store, "alice", "later")
+		for _, data := range []*BlogItem{due, later} {
+			if _, err := store.SetStatus(ctx, data.ID, statusDraft, statusInReview, "", testTime); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var statusErr *StatusError
+		if _, err := store.SchedulePublish(ctx, draft.ID, testTime, "bob", testTime); !errors.As(err, &statusErr) {
+			t.Errorf("SchedulePublish of a draft returned %v, want a *StatusError", err)
+		}
+		scheduled, err := store.SchedulePublish(ctx, due.ID, testTime, "bob", testTime)
+		if err != nil || scheduled.PublishAt == nil || !scheduled.PublishAt.Equal(testTime) || scheduled.Revision != 3 {
+			t.Fatalf("SchedulePublish returned %+v, %v, want version 3", scheduled, err)
+		}
+		if _, err := store.SchedulePublish(ctx, later.ID, testTime.Add(time.Hour), "bob", testTime); err != nil {
+			t.Fatal(err)
+		}
+
+		published, err := store.PublishDue(ctx, testTime.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("PublishDue: %v", err)
+		}
+		if len(published) != 1 || published[0].ID != due.ID || published[0].status() != statusPublished || published[0].PublishAt != nil {
+			t.Fatalf("PublishDue returned %+v, want the due blog published", published)
+		}
+		if published[0].Revision != 4 {
+			t.Errorf("PublishDue returned the blog at version %d, want 4", published[0].Revision)
+		}
+		if got, err := store.Get(ctx, due.ID); err != nil || got.Revision != 4 || got.status() != statusPublished {
+			t.Errorf("Get after PublishDue returned %+v, %v", got, err)
+		}
+		// the submission, the schedule and the publication each recorded a revision, by the last editor
+		if got := revisionNumbers(t, store, due.ID); !equalNumbers(got, []int64{1, 2, 3, 4}) {
+			t.Errorf("revisions are %v, want [1 2 3 4]", got)
+		}
+		rev, err := store.GetRevision(ctx, due.ID, 4)
+		if err != nil || rev.EditorID != "bob" || !rev.CreatedAt.Equal(testTime.Add(time.Minute)) {
+			t.Errorf("revision of the publication is %+v, %v", rev, err)
+		}
+		if again, err := store.PublishDue(ctx, testTime.Add(time.Minute)); err != nil || len(again) != 0 {
+			t.Errorf("PublishDue published %d blogs twice, %v", len(again), err)
+		}
+		if got, err := store.Get(ctx, later.ID); err != nil || got.status() != statusInReview || got.PublishAt == nil {
+			t.Errorf("the blog scheduled later is %+v, %v", got, err)
+		}
+
+		// moving a scheduled blog by hand cancels its publication
+		if _, err := store.SetStatus(ctx, later.ID, statusInReview, statusPublished, "", testTime); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := store.Get(ctx, later.ID); err != nil || got.PublishAt != nil {
+			t.Errorf("the blog published by hand is still scheduled: %+v, %v", got, err)
+		}
+	})
+}
+
+func TestStoreList(t *testing.T) {
+	titles := func(t *testing.T, store BlogStore, q ListQuery) []string {
+		t.Helper()
+		var got []string
+		err := store.List(context.Background(), q, func(data *BlogItem) error {
+			got = append(got, data.Title)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		return got
+	}
+
+	forEachStore(t, func(t *testing.T, store BlogStore) {
+		ctx := context.Background()
+		var blogs []*BlogItem
+		for _, b := range []struct{ author, title string }{{"bob", "go b"}, {"alice", "go a"}, {"alice", "rust"}, {"carol", "gone"}} {
+			blogs = append(blogs, createBlog(t, store, b.author, b.title))
+		}
+		if err := store.Delete(ctx, blogs[3].ID, 0, testTime); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.SetStatus(ctx, blogs[2].ID, statusDraft, statusInReview, "", testTime); err != nil {
+			t.Fatal(err)
+		}
+
+		byTitle := ListQuery{SortBy: blogpb.ListBlogReq_TITLE}
+		tests := []struct {
+			name string
+			q    ListQuery
+			want []string
+		}{
+			{"by id", ListQuery{}, []string{"go b", "go a", "rust"}},
+			{"by title", byTitle, []string{"go a", "go b", "rust"}},
+			{"by title desc", ListQuery{SortBy: blogpb.ListBlogReq_TITLE, Desc: true}, []string{"rust", "go b", "go a"}},
+			{"by author", ListQuery{AuthorID: "alice", SortBy: blogpb.ListBlogReq_TITLE}, []string{"go a", "rust"}},
+			{"by title prefix", ListQuery{TitlePrefix: "go", SortBy: blogpb.ListBlogReq_TITLE}, []string{"go a", "go b"}},
+			{"by status", ListQuery{Status: statusInReview}, []string{"rust"}},
+			{"limited", ListQuery{SortBy: blogpb.ListBlogReq_TITLE, Limit: 2}, []string{"go a", "go b"}},
+			{"the trash", ListQuery{Deleted: true}, []string{"gone"}},
+			{"after a cursor", ListQuery{SortBy: blogpb.ListBlogReq_TITLE, After: &listCursor{Value: "go a", ID: blogs[1].ID.Hex()}}, []string{"go b", "rust"}},
+			{"after a cursor desc", ListQuery{SortBy: blogpb.ListBlogReq_TITLE, Desc: true, After: &listCursor{Value: "go b", ID: blogs[0].ID.Hex()}}, []string{"go a"}},
+		}
+		for _, tt := range tests {
+			if got := titles(t, store, tt.q); !equalStrings(got, tt.want) {
+				t.Errorf("%s: List returned %v, want %v", tt.name, got, tt.want)
+			}
+		}
+
+		// an error of fn stops the listing
+		stop := errors.New("stop")
+		calls := 0
+		err := store.List(ctx, byTitle, func(*BlogItem) error {
+			calls++
+			return stop
+		})
+		if err != stop || calls != 1 {
+			t.Errorf("List returned %v after %d calls, want the error of fn after 1", err, calls)
+		}
+	})
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMemoryStoreIndexFailure(t *testing.T) {
+	store, err := newMemoryStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	created := createBlog(t, store, "alice", "first")
+	deleted := createBlog(t, store, "alice", "deleted")
+	if err := store.Delete(ctx, deleted.ID, 0, testTime); err != nil {
+		t.Fatal(err)
+	}
+	// a closed index fails every change, the blogs must stay as they were
+	store.index.index.Close()
+
+	if _, err := store.Create(ctx, &BlogItem{AuthorID: "alice", Title: "second"}); err == nil {
+		t.Errorf("Create succeeded without indexing the blog")
+	}
+	if _, err := store.Update(ctx, &BlogItem{ID: created.ID, Title: "changed"}, []string{"title"}, 0); err == nil {
+		t.Errorf("Update succeeded without indexing the blog")
+	}
+	if err := store.Delete(ctx, created.ID, 0, testTime); err == nil {
+		t.Errorf("Delete succeeded without removing the blog from the index")
+	}
+	if _, err := store.Undelete(ctx, deleted.ID); err == nil {
+		t.Errorf("Undelete succeeded without indexing the blog")
+	}
+
+	got, err := store.Get(ctx, created.ID)
+	if err != nil || got.Title != "first" || got.Revision != 1 {
+		t.Errorf("Get returned %+v, %v, want the blog as created", got, err)
+	}
+	if got := revisionNumbers(t, store, created.ID); !equalNumbers(got, []int64{1}) {
+		t.Errorf("revisions are %v, want [1]", got)
+	}
+	if _, err := store.GetDeleted(ctx, deleted.ID); err != nil {
+		t.Errorf("the deleted blog left the trash: %v", err)
+	}
+	if n := len(store.blogs); n != 2 {
+		t.Errorf("the store holds %d blogs, want 2", n)
+	}
+}
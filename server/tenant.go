@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tenantMetadata names the tenant of a call, the "X-Tenant-Id" header over REST.
+// Authenticated callers belong to the tenant of their credentials, the metadata can only repeat it.
+const tenantMetadata = "x-tenant-id"
+
+// tenantClaim is the JWT claim naming the tenant of a user
+const tenantClaim = "tenant"
+
+// tenantOpenTimeout bounds the opening of the store of a tenant, which no call can cancel
+const tenantOpenTimeout = 30 * time.Second
+
+// tenantPattern is what tenant ids look like, they end up in collection, database and file names
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,47}$`)
+
+type tenantKey struct{}
+
+// withTenant returns a copy of ctx carrying the tenant of the call, the log of the call records it too
+func withTenant(ctx context.Context, tenant string) context.Context {
+	if l := callLogFromContext(ctx); l != nil {
+		l.tenant = tenant
+	}
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// tenantFromContext returns the tenant of the call, blank when there is a single tenant
+func tenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// tenantResolver finds the tenant of every call, it runs after the authentication interceptors
+type tenantResolver struct {
+	// defaultTenant is the tenant of the calls naming none, blank to reject them
+	defaultTenant string
+}
+
+func (r *tenantResolver) resolve(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requested := ""
+	if values := md.Get(tenantMetadata); len(values) > 0 {
+		requested = values[0]
+	}
+
+	tenant := requested
+	if p := principalFromContext(ctx); p != nil {
+		// the credentials decide, a caller can't reach the blogs of another tenant by asking for them
+		tenant = p.Tenant
+		if tenant == "" {
+			tenant = r.defaultTenant
+		}
+		if requested != "" && requested != tenant {
+			return "", status.Errorf(codes.PermissionDenied, fmt.Sprintf("%s belongs to tenant %q, not %q", p.Subject, tenant, requested))
+		}
+	}
+	if tenant == "" {
+		tenant = r.defaultTenant
+	}
+
+	if tenant == "" {
+		return "", status.Errorf(codes.InvalidArgument, fmt.Sprintf("No tenant, set the %s metadata or use credentials with a %q claim", tenantMetadata, tenantClaim))
+	}
+	if !tenantPattern.MatchString(tenant) {
+		return "", status.Errorf(codes.InvalidArgument, fmt.Sprintf("Invalid tenant %q, it should match %s", tenant, tenantPattern))
+	}
+	return tenant, nil
+}
+
+// unaryInterceptor puts the tenant of the call in the context of the handler
+func (r *tenantResolver) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if isUnauthenticatedMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	tenant, err := r.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(withTenant(ctx, tenant), req)
+}
+
+// streamInterceptor is unaryInterceptor for the streaming calls
+func (r *tenantResolver) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if isUnauthenticatedMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+
+	tenant, err := r.resolve(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &contextStream{ServerStream: ss, ctx: withTenant(ss.Context(), tenant)})
+}
+
+// tenantStores gives every tenant its own BlogStore, which can only reach the blogs of this tenant.
+// Stores are opened on first use and kept.
+type tenantStores struct {
+	mu     sync.Mutex
+	stores map[string]BlogStore
+	// opening holds the stores being opened, a store is opened once even when several calls want it at the same time
+	opening map[string]*tenantOpening
+
+	open func(ctx context.Context, tenant string) (BlogStore, error)
+	// known lists the tenants having blogs in the backend, so the background jobs also reach the tenants nobody called yet
+	known func(ctx context.Context) ([]string, error)
+}
+
+func newTenantStores(open func(ctx context.Context, tenant string) (BlogStore, error), known func(ctx context.Context) ([]string, error)) *tenantStores {
+	return &tenantStores{stores: make(map[string]BlogStore), opening: make(map[string]*tenantOpening), open: open, known: known}
+}
+
+// tenantOpening is a store being opened, the calls wanting it wait for done
+type tenantOpening struct {
+	done  chan struct{}
+	store BlogStore
+	err   error
+}
+
+// singleTenant holds the blogs of every call in store, for servers without tenancy
+func singleTenant(store BlogStore) *tenantStores {
+	t := newTenantStores(func(context.Context, string) (BlogStore, error) {
+		return nil, fmt.Errorf("tenancy is disabled")
+	}, func(context.Context) ([]string, error) {
+		return nil, nil
+	})
+	t.stores[""] = store
+	return t
+}
+
+// get returns the store of a tenant, opening it if needed
+func (t *tenantStores) get(ctx context.Context, tenant string) (BlogStore, error) {
+	t.mu.Lock()
+	if store, ok := t.stores[tenant]; ok {
+		t.mu.Unlock()
+		return store, nil
+	}
+	if !tenantPattern.MatchString(tenant) {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("invalid tenant %q", tenant)
+	}
+	// a bolt file can only be opened once, a second open would wait for the lock of the first one
+	opening, ok := t.opening[tenant]
+	if !ok {
+		opening = &tenantOpening{done: make(chan struct{})}
+		t.opening[tenant] = opening
+		go t.openTenant(tenant, opening)
+	}
+	t.mu.Unlock()
+
+	// every call waits on its own context, the open goes on for the others when the call that started it gives up
+	select {
+	case <-opening.done:
+		return opening.store, opening.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// openTenant opens the store of a tenant for the calls waiting on opening.
+// It isn't bound to any of them, only to tenantOpenTimeout.
+func (t *tenantStores) openTenant(tenant string, opening *tenantOpening) {
+	ctx, cancel := context.WithTimeout(context.Background(), tenantOpenTimeout)
+	defer cancel()
+
+	// opening can take a while (Mongo creates the indexes), the other tenants must not wait for it
+	store, err := t.open(ctx, tenant)
+	if err != nil {
+		err = fmt.Errorf("could not open the store of tenant %s: %w", tenant, err)
+	}
+
+	t.mu.Lock()
+	// a failed open is forgotten, the next call tries again
+	if err == nil {
+		t.stores[tenant] = store
+	}
+	delete(t.opening, tenant)
+	t.mu.Unlock()
+
+	opening.store, opening.err = store, err
+	close(opening.done)
+}
+
+// all returns the tenants, in order, with their store
+func (t *tenantStores) all(ctx context.Context) ([]string, []BlogStore, error) {
+	known, err := t.known(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t.mu.Lock()
+	tenants := make([]string, 0, len(t.stores)+len(known))
+	for tenant := range t.stores {
+		tenants = append(tenants, tenant)
+	}
+	t.mu.Unlock()
+	for _, tenant := range known {
+		if !containsField(tenants, tenant) && tenantPattern.MatchString(tenant) {
+			tenants = append(tenants, tenant)
+		}
+	}
+	sort.Strings(tenants)
+
+	stores := make([]BlogStore, len(tenants))
+	for i, tenant := range tenants {
+		if stores[i], err = t.get(ctx, tenant); err != nil {
+			return nil, nil, err
+		}
+	}
+	return tenants, stores, nil
+}
+
+// Close closes the stores that need it
+func (t *tenantStores) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var first error
+	for _, store := range t.stores {
+		if closer, ok := store.(io.Closer); ok {
+			if err := closer.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// storeOf returns the store of the tenant of the call, handlers reach the blogs through it only
+func (s *BlogServiceServer) storeOf(ctx context.Context) (BlogStore, error) {
+	store, err := s.stores.get(ctx, tenantFromContext(ctx))
+	if err != nil {
+		return nil, storeError(err, "Could not reach the blogs")
+	}
+	return store, nil
+}
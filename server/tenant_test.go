@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// getConcurrently gets the store of tenant from n goroutines at once
+func getConcurrently(t *testing.T, stores *tenantStores, tenant string, n int) ([]BlogStore, []error) {
+	t.Helper()
+	got := make([]BlogStore, n)
+	errs := make([]error, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			got[i], errs[i] = stores.get(context.Background(), tenant)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	return got, errs
+}
+
+func TestTenantStoresOpenOnce(t *testing.T) {
+	var opened int32
+	stores := newTenantStores(func(ctx context.Context, tenant string) (BlogStore, error) {
+		atomic.AddInt32(&opened, 1)
+		// slow enough for every goroutine to ask for the store while it opens
+		time.Sleep(50 * time.Millisecond)
+		return newMemoryStore()
+	}, func(context.Context) ([]string, error) {
+		return nil, nil
+	})
+
+	got, errs := getConcurrently(t, stores, "acme", 10)
+	for i := range got {
+		if errs[i] != nil {
+			t.Fatalf("get: %v", errs[i])
+		}
+		if got[i] != got[0] {
+			t.Fatalf("get returned different stores for the same tenant")
+		}
+	}
+	if n := atomic.LoadInt32(&opened); n != 1 {
+		t.Errorf("the store was opened %d times, want once", n)
+	}
+}
+
+func TestTenantStoresRetryFailedOpen(t *testing.T) {
+	var opened int32
+	stores := newTenantStores(func(ctx context.Context, tenant string) (BlogStore, error) {
+		if atomic.AddInt32(&opened, 1) == 1 {
+			return nil, errors.New("unreachable")
+		}
+		return newMemoryStore()
+	}, func(context.Context) ([]string, error) {
+		return nil, nil
+	})
+
+	if _, err := stores.get(context.Background(), "acme"); err == nil {
+		t.Fatal("get returned no error when the open failed")
+	}
+	if _, err := stores.get(context.Background(), "acme"); err != nil {
+		t.Fatalf("get after a failed open: %v", err)
+	}
+}
+
+func TestTenantStoresFirstCallerGivesUp(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var openErr error
+	stores := newTenantStores(func(ctx context.Context, tenant string) (BlogStore, error) {
+		close(started)
+		<-release
+		// the open belongs to no call, the first one giving up must not cancel it
+		openErr = ctx.Err()
+		return newMemoryStore()
+	}, func(context.Context) ([]string, error) {
+		return nil, nil
+	})
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := stores.get(first, "acme")
+		firstErr <- err
+	}()
+	<-started
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := stores.get(context.Background(), "acme")
+		waiter <- err
+	}()
+
+	cancel()
+	if err := <-firstErr; err != context.Canceled {
+		t.Errorf("get of the canceled call returned %v, want context.Canceled", err)
+	}
+	close(release)
+	if err := <-waiter; err != nil {
+		t.Fatalf("get of the waiting call returned %v after the first call gave up", err)
+	}
+	if openErr != nil {
+		t.Errorf("the open ran on a done context: %v", openErr)
+	}
+	if _, ok := stores.stores["acme"]; !ok {
+		t.Errorf("the opened store was not kept")
+	}
+}
+
+func TestBoltTenantsConcurrentFirstOpen(t *testing.T) {
+	stores := newBoltTenants(filepath.Join(t.TempDir(), "blog.db"))
+	defer stores.Close()
+	// the file opens in no time when empty, slow it down like a big one being indexed
+	open := stores.open
+	stores.open = func(ctx context.Context, tenant string) (BlogStore, error) {
+		time.Sleep(50 * time.Millisecond)
+		return open(ctx, tenant)
+	}
+
+	// bbolt locks its file, a second open of the same tenant would wait for the first one to close it and time out
+	got, errs := getConcurrently(t, stores, "acme", 8)
+	for i := range got {
+		if errs[i] != nil {
+			t.Fatalf("get: %v", errs[i])
+		}
+		if got[i] != got[0] {
+			t.Fatalf("get returned different stores for the same tenant")
+		}
+	}
+
+	if _, err := got[0].Create(context.Background(), &BlogItem{AuthorID: "alice", Title: "first"}); err != nil {
+		t.Errorf("Create in the store of the tenant: %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+)
+
+// certReloader holds the certificate of the server and the CAs of the clients, reloaded when their files change.
+// Every handshake gets the latest ones, so rotating a certificate doesn't need a restart.
+type certReloader struct {
+	certFile, keyFile, clientCAFile string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool // nil when clients aren't verified
+	stamp     string         // sizes and modification times of the files loaded
+	failed    string         // stamp of the files that last failed to load, they aren't tried again until they change
+}
+
+// newCertReloader loads the files once, a server must not start with a broken certificate
+func newCertReloader(certFile, keyFile, clientCAFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// files are the paths watched for changes
+func (r *certReloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+	return files
+}
+
+// reload loads the files again if they changed since the last load and tells whether it did.
+// On error the previous certificate and CAs stay in use.
+func (r *certReloader) reload() (bool, error) {
+	var stamp strings.Builder
+	for _, file := range r.files() {
+		// Stat follows symlinks, so certificates swapped by a symlink (like Kubernetes secrets) are seen too
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprintf(&stamp, "%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+
+	r.mu.RLock()
+	unchanged := stamp.String() == r.stamp || stamp.String() == r.failed
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, clientCAs, err := r.load()
+	if err != nil {
+		r.mu.Lock()
+		r.failed = stamp.String()
+		r.mu.Unlock()
+		return false, err
+	}
+
+	r.mu.Lock()
+	r.cert, r.clientCAs, r.stamp = cert, clientCAs, stamp.String()
+	r.mu.Unlock()
+	return true, nil
+}
+
+func (r *certReloader) load() (*tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load the certificate: %w", err)
+	}
+	if r.clientCAFile == "" {
+		return &cert, nil, nil
+	}
+
+	pem, err := ioutil.ReadFile(r.clientCAFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read the client CA bundle: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(pem) {
+		return nil, nil, fmt.Errorf("no certificate found in the client CA bundle %s", r.clientCAFile)
+	}
+	return &cert, clientCAs, nil
+}
+
+// run checks the files for changes every interval until ctx is done
+func (r *certReloader) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := r.reload()
+		if err != nil {
+			zap.L().Error("Could not reload the TLS files, keeping the previous ones", zap.Error(err))
+		} else if reloaded {
+			zap.L().Info("Reloaded the TLS certificate", zap.String("file", r.certFile))
+		}
+	}
+}
+
+// config returns a TLS configuration serving the current certificate, for connections negotiating one of nextProtos.
+// Clients must present a certificate signed by one of the CAs when a CA bundle is configured.
+func (r *certReloader) config(nextProtos ...string) *tls.Config {
+	current := func() *tls.Config {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		config := &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{*r.cert},
+			NextProtos:   nextProtos,
+		}
+		if r.clientCAs != nil {
+			config.ClientCAs = r.clientCAs
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		return config
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return current(), nil
+		},
+	}
+}
+
+// gatewayCredentials secures every connection with TLS except the ones of the REST gateway,
+// which come from inside the process through a pipe (see newGateway) and never touch the network
+type gatewayCredentials struct {
+	credentials.TransportCredentials
+}
+
+// gatewayAuthInfo is the AuthInfo of the connections of the gateway
+type gatewayAuthInfo struct {
+	credentials.CommonAuthInfo
+}
+
+func (gatewayAuthInfo) AuthType() string {
+	return "gateway"
+}
+
+func (c gatewayCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if isGatewayConn(conn) {
+		return conn, gatewayAuthInfo{CommonAuthInfo: credentials.CommonAuthInfo{SecurityLevel: credentials.PrivacyAndIntegrity}}, nil
+	}
+	return c.TransportCredentials.ServerHandshake(conn)
+}
+
+func (c gatewayCredentials) Clone() credentials.TransportCredentials {
+	return gatewayCredentials{TransportCredentials: c.TransportCredentials.Clone()}
+}
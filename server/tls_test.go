@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// selfSigned returns the PEM of a new self-signed certificate for name and of its key
+func selfSigned(t *testing.T, name string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFileAt writes a file and sets its modification time, so a rewrite within the same tick is still seen
+func writeFileAt(t *testing.T, path string, content []byte, at time.Time) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// servedName returns the common name of the certificate a handshake would get
+func servedName(t *testing.T, r *certReloader) string {
+	t.Helper()
+	config, err := r.config().GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.pem")
+	firstCert, firstKey := selfSigned(t, "first")
+	secondCert, secondKey := selfSigned(t, "second")
+	caCert, _ := selfSigned(t, "ca")
+
+	start := time.Now().Add(-time.Minute)
+	writeFileAt(t, certFile, firstCert, start)
+	writeFileAt(t, keyFile, firstKey, start)
+	writeFileAt(t, caFile, caCert, start)
+	r, err := newCertReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("newCertReloader: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		change       func(at time.Time)
+		wantReloaded bool
+		wantErr      bool
+		wantName     string
+	}{
+		{name: "unchanged", change: func(time.Time) {}, wantName: "first"},
+		{
+			name: "rotated",
+			change: func(at time.Time) {
+				writeFileAt(t, certFile, secondCert, at)
+				writeFileAt(t, keyFile, secondKey, at)
+			},
+			wantReloaded: true, wantName: "second",
+		},
+		{
+			// the new certificate is written before its key
+			name:    "half rotated",
+			change:  func(at time.Time) { writeFileAt(t, certFile, firstCert, at) },
+			wantErr: true, wantName: "second",
+		},
+		{name: "still half rotated", change: func(time.Time) {}, wantName: "second"},
+		{name: "key written", change: func(at time.Time) { writeFileAt(t, keyFile, firstKey, at) }, wantReloaded: true, wantName: "first"},
+		{name: "broken CA bundle", change: func(at time.Time) { writeFileAt(t, caFile, []byte("not a certificate"), at) }, wantErr: true, wantName: "first"},
+		{name: "CA bundle fixed", change: func(at time.Time) { writeFileAt(t, caFile, caCert, at) }, wantReloaded: true, wantName: "first"},
+		{name: "key removed", change: func(time.Time) { os.Remove(keyFile) }, wantErr: true, wantName: "first"},
+	}
+	for i, tt := range tests {
+		tt.change(start.Add(time.Duration(i+1) * time.Second))
+		reloaded, err := r.reload()
+		if reloaded != tt.wantReloaded || (err != nil) != tt.wantErr {
+			t.Errorf("%s: reload returned %v, %v, want %v and an error %v", tt.name, reloaded, err, tt.wantReloaded, tt.wantErr)
+		}
+		if got := servedName(t, r); got != tt.wantName {
+			t.Errorf("%s: the served certificate is %q, want %q", tt.name, got, tt.wantName)
+		}
+	}
+}
+
+func TestCertReloaderClientCAs(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key"), filepath.Join(dir, "ca.pem")
+	cert, key := selfSigned(t, "server")
+	caCert, _ := selfSigned(t, "ca")
+	writeFileAt(t, certFile, cert, time.Now())
+	writeFileAt(t, keyFile, key, time.Now())
+	writeFileAt(t, caFile, caCert, time.Now())
+
+	tests := []struct {
+		name         string
+		clientCAFile string
+		wantAuth     tls.ClientAuthType
+	}{
+		{"no client CAs", "", tls.NoClientCert},
+		{"client CAs", caFile, tls.RequireAndVerifyClientCert},
+	}
+	for _, tt := range tests {
+		r, err := newCertReloader(certFile, keyFile, tt.clientCAFile)
+		if err != nil {
+			t.Fatalf("%s: newCertReloader: %v", tt.name, err)
+		}
+		config, err := r.config("h2").GetConfigForClient(&tls.ClientHelloInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.ClientAuth != tt.wantAuth || (config.ClientCAs != nil) != (tt.clientCAFile != "") {
+			t.Errorf("%s: client auth is %v, want %v", tt.name, config.ClientAuth, tt.wantAuth)
+		}
+		if len(config.NextProtos) != 1 || config.NextProtos[0] != "h2" || config.MinVersion != tls.VersionTLS12 {
+			t.Errorf("%s: config negotiates %v from %x", tt.name, config.NextProtos, config.MinVersion)
+		}
+	}
+
+	// a server doesn't start with a broken certificate
+	writeFileAt(t, keyFile, bytes.Repeat([]byte("x"), 10), time.Now())
+	if _, err := newCertReloader(certFile, keyFile, ""); err == nil {
+		t.Error("newCertReloader accepted a broken key")
+	}
+}
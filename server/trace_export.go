@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpExportTimeout bounds how long a batch of spans may take to reach the collector
+const otlpExportTimeout = 10 * time.Second
+
+// otlpExperimental is the tracing exporter posting the spans with otlpExporter, it has to be asked for by this name
+const otlpExperimental = "otlp-experimental"
+
+// otlpExporter posts the spans to an OpenTelemetry collector with OTLP/HTTP, in its JSON encoding.
+//
+// It is experimental: the upstream otlptracehttp exporter can't be used yet, its v1.7.0, the one matching the SDK,
+// needs grpc v1.46 and protobuf v1.28 (through go.opentelemetry.io/proto/otlp, which also brings grpc-gateway/v2),
+// while the server, its generated code and the v1 gateway are pinned to grpc v1.33 and protobuf v1.25.
+// The JSON encoding needs none of the generated OTLP messages, the few types below follow opentelemetry-proto,
+// without the retries, compression and partial success handling of upstream.
+type otlpExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newOTLPExporter(endpoint string) *otlpExporter {
+	return &otlpExporter{endpoint: endpoint, client: &http.Client{Timeout: otlpExportTimeout}}
+}
+
+// the messages of the OTLP trace service, as encoded in JSON: ids in hex, 64 bits integers as strings
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Events            []otlpEvent    `json:"events,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpEvent struct {
+		TimeUnixNano string         `json:"timeUnixNano"`
+		Name         string         `json:"name"`
+		Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string      `json:"key"`
+		Value interface{} `json:"value"`
+	}
+)
+
+// otlpStatusCodes maps the status codes of the API to the ones of OTLP, which has Ok and Error the other way round
+var otlpStatusCodes = map[otelcodes.Code]int{otelcodes.Unset: 0, otelcodes.Ok: 1, otelcodes.Error: 2}
+
+func (e *otlpExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	// the spans of a server share its resource, they are grouped by instrumentation library
+	var scopes []otlpScopeSpans
+	index := make(map[string]int)
+	for _, span := range spans {
+		lib := span.InstrumentationLibrary()
+		i, ok := index[lib.Name+"@"+lib.Version]
+		if !ok {
+			i = len(scopes)
+			index[lib.Name+"@"+lib.Version] = i
+			scopes = append(scopes, otlpScopeSpans{Scope: otlpScope{Name: lib.Name, Version: lib.Version}})
+		}
+		scopes[i].Spans = append(scopes[i].Spans, toOTLPSpan(span))
+	}
+	body, err := json.Marshal(otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: toOTLPAttributes(spans[0].Resource().Attributes())},
+		ScopeSpans: scopes,
+	}}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not export %d spans: %w", len(spans), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("could not export %d spans: %s: %s", len(spans), resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+func (e *otlpExporter) Shutdown(ctx context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+func toOTLPSpan(span sdktrace.ReadOnlySpan) otlpSpan {
+	out := otlpSpan{
+		TraceID:           span.SpanContext().TraceID().String(),
+		SpanID:            span.SpanContext().SpanID().String(),
+		Name:              span.Name(),
+		Kind:              int(span.SpanKind()),
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime().UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.EndTime().UnixNano(), 10),
+		Attributes:        toOTLPAttributes(span.Attributes()),
+		Status:            otlpStatus{Code: otlpStatusCodes[span.Status().Code], Message: span.Status().Description},
+	}
+	if span.Parent().IsValid() {
+		out.ParentSpanID = span.Parent().SpanID().String()
+	}
+	for _, ev := range span.Events() {
+		out.Events = append(out.Events, otlpEvent{
+			TimeUnixNano: strconv.FormatInt(ev.Time.UnixNano(), 10),
+			Name:         ev.Name,
+			Attributes:   toOTLPAttributes(ev.Attributes),
+		})
+	}
+	return out
+}
+
+func toOTLPAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		out = append(out, otlpKeyValue{Key: string(attr.Key), Value: toOTLPValue(attr.Value)})
+	}
+	return out
+}
+
+// toOTLPValue is the AnyValue of an attribute value
+func toOTLPValue(v attribute.Value) interface{} {
+	array := func(values []interface{}) interface{} {
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	}
+	switch v.Type() {
+	case attribute.BOOL:
+		return map[string]interface{}{"boolValue": v.AsBool()}
+	case attribute.INT64:
+		return map[string]interface{}{"intValue": strconv.FormatInt(v.AsInt64(), 10)}
+	case attribute.FLOAT64:
+		return map[string]interface{}{"doubleValue": v.AsFloat64()}
+	case attribute.BOOLSLICE:
+		var values []interface{}
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, toOTLPValue(attribute.BoolValue(b)))
+		}
+		return array(values)
+	case attribute.INT64SLICE:
+		var values []interface{}
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, toOTLPValue(attribute.Int64Value(i)))
+		}
+		return array(values)
+	case attribute.FLOAT64SLICE:
+		var values []interface{}
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, toOTLPValue(attribute.Float64Value(f)))
+		}
+		return array(values)
+	case attribute.STRINGSLICE:
+		var values []interface{}
+		for _, s := range v.AsStringSlice() {
+			values = append(values, toOTLPValue(attribute.StringValue(s)))
+		}
+		return array(values)
+	default:
+		return map[string]interface{}{"stringValue": v.Emit()}
+	}
+}
+
+// jsonExporter writes every span as a line of JSON, for development: to stdout, or to a file to look at later
+type jsonExporter struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer // nil for stdout, which must stay open
+}
+
+func newJSONExporter(w io.Writer, closer io.Closer) *jsonExporter {
+	return &jsonExporter{enc: json.NewEncoder(w), closer: closer}
+}
+
+// jsonSpan is a span as the json exporter writes it
+type jsonSpan struct {
+	TraceID      string                 `json:"trace_id"`
+	SpanID       string                 `json:"span_id"`
+	ParentSpanID string                 `json:"parent_span_id,omitempty"`
+	Name         string                 `json:"name"`
+	Kind         string                 `json:"kind"`
+	Start        time.Time              `json:"start"`
+	DurationMS   float64                `json:"duration_ms"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+	Status       string                 `json:"status,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+}
+
+func (e *jsonExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, span := range spans {
+		out := jsonSpan{
+			TraceID:    span.SpanContext().TraceID().String(),
+			SpanID:     span.SpanContext().SpanID().String(),
+			Name:       span.Name(),
+			Kind:       span.SpanKind().String(),
+			Start:      span.StartTime(),
+			DurationMS: float64(span.EndTime().Sub(span.StartTime())) / float64(time.Millisecond),
+			Attributes: make(map[string]interface{}, len(span.Attributes())),
+			Error:      span.Status().Description,
+		}
+		if span.Parent().IsValid() {
+			out.ParentSpanID = span.Parent().SpanID().String()
+		}
+		if span.Status().Code != otelcodes.Unset {
+			out.Status = span.Status().Code.String()
+		}
+		for _, attr := range span.Attributes() {
+			out.Attributes[string(attr.Key)] = attr.Value.AsInterface()
+		}
+		if err := e.enc.Encode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *jsonExporter) Shutdown(ctx context.Context) error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exportedBatch posts spans with an otlpExporter to a fake collector and returns what it received
+func exportedBatch(t *testing.T, spans []sdktrace.ReadOnlySpan) otlpRequest {
+	t.Helper()
+	var received otlpRequest
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("collector got a %s with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("could not read the batch: %v", err)
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("could not decode the batch: %v", err)
+		}
+	}))
+	defer collector.Close()
+
+	exporter := newOTLPExporter(collector.URL + "/v1/traces")
+	defer exporter.Shutdown(context.Background())
+	if err := exporter.ExportSpans(context.Background(), spans); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+	return received
+}
+
+func TestOTLPExporter(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	parentID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	childID, _ := trace.SpanIDFromHex("b7ad6b7169203331")
+	parent := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: parentID, TraceFlags: trace.FlagsSampled})
+	child := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: childID, TraceFlags: trace.FlagsSampled})
+	start := time.Date(2021, 3, 1, 12, 0, 0, 123456789, time.UTC)
+	res := resource.NewSchemaless(attribute.String("service.name", "blog"))
+	lib := instrumentation.Library{Name: tracerName}
+
+	spans := tracetest.SpanStubs{
+		{
+			Name:        "blog.BlogService/ReadBlog",
+			SpanContext: parent,
+			SpanKind:    trace.SpanKindServer,
+			StartTime:   start,
+			EndTime:     start.Add(3 * time.Millisecond),
+			Attributes: []attribute.KeyValue{
+				attribute.String("rpc.method", "ReadBlog"),
+				attribute.Int64("rpc.grpc.status_code", 5),
+				attribute.Bool("retried", false),
+				attribute.Float64("ratio", 0.5),
+				attribute.StringSlice("tags", []string{"a", "b"}),
+			},
+			Status:                 sdktrace.Status{Code: otelcodes.Error, Description: "blog not found"},
+			Resource:               res,
+			InstrumentationLibrary: lib,
+		},
+		{
+			Name:                   "mongo find",
+			SpanContext:            child,
+			Parent:                 parent,
+			SpanKind:               trace.SpanKindClient,
+			StartTime:              start.Add(time.Millisecond),
+			EndTime:                start.Add(2 * time.Millisecond),
+			Events:                 []sdktrace.Event{{Name: "retry", Time: start.Add(1500 * time.Microsecond)}},
+			Status:                 sdktrace.Status{Code: otelcodes.Ok},
+			Resource:               res,
+			InstrumentationLibrary: lib,
+		},
+	}.Snapshots()
+
+	batch := exportedBatch(t, spans)
+	if len(batch.ResourceSpans) != 1 || len(batch.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("batch is %+v, want one resource with one scope", batch)
+	}
+	resourceSpans := batch.ResourceSpans[0]
+	if attrs := resourceSpans.Resource.Attributes; len(attrs) != 1 || attrs[0].Key != "service.name" {
+		t.Errorf("resource attributes are %+v", attrs)
+	}
+	scope := resourceSpans.ScopeSpans[0]
+	if scope.Scope.Name != tracerName || len(scope.Spans) != 2 {
+		t.Fatalf("scope is %+v, want the 2 spans of %s", scope, tracerName)
+	}
+
+	server, client := scope.Spans[0], scope.Spans[1]
+	nanos := func(t time.Time) string { return strconv.FormatInt(t.UnixNano(), 10) }
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"trace id", server.TraceID, "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"span id", server.SpanID, "00f067aa0ba902b7"},
+		{"parent span id of the root", server.ParentSpanID, ""},
+		{"trace id of the child", client.TraceID, "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"span id of the child", client.SpanID, "b7ad6b7169203331"},
+		{"parent span id of the child", client.ParentSpanID, "00f067aa0ba902b7"},
+		{"kind", server.Kind, 2},
+		{"kind of the child", client.Kind, 3},
+		{"start", server.StartTimeUnixNano, "1614600000123456789"},
+		{"end", server.EndTimeUnixNano, nanos(start.Add(3 * time.Millisecond))},
+		{"status code", server.Status.Code, 2},
+		{"status message", server.Status.Message, "blog not found"},
+		{"status code of the child", client.Status.Code, 1},
+		{"event", len(client.Events), 1},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s is %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if len(client.Events) == 1 && (client.Events[0].Name != "retry" || client.Events[0].TimeUnixNano != nanos(start.Add(1500*time.Microsecond))) {
+		t.Errorf("event is %+v", client.Events[0])
+	}
+
+	// the values are AnyValues, 64 bits integers as strings
+	wantAttributes := map[string]string{
+		"rpc.method":           `{"stringValue":"ReadBlog"}`,
+		"rpc.grpc.status_code": `{"intValue":"5"}`,
+		"retried":              `{"boolValue":false}`,
+		"ratio":                `{"doubleValue":0.5}`,
+		"tags":                 `{"arrayValue":{"values":[{"stringValue":"a"},{"stringValue":"b"}]}}`,
+	}
+	if len(server.Attributes) != len(wantAttributes) {
+		t.Errorf("attributes are %+v, want %d of them", server.Attributes, len(wantAttributes))
+	}
+	for _, attr := range server.Attributes {
+		got, err := json.Marshal(attr.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != wantAttributes[attr.Key] {
+			t.Errorf("attribute %s is %s, want %s", attr.Key, got, wantAttributes[attr.Key])
+		}
+	}
+}
+
+func TestOTLPExporterRejected(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad batch", http.StatusBadRequest)
+	}))
+	defer collector.Close()
+
+	spans := tracetest.SpanStubs{{Name: "span", Resource: resource.Empty()}}.Snapshots()
+	err := newOTLPExporter(collector.URL).ExportSpans(context.Background(), spans)
+	if err == nil {
+		t.Fatal("ExportSpans returned no error when the collector rejected the batch")
+	}
+}
+
+func TestOTLPExporterOptIn(t *testing.T) {
+	tests := []struct {
+		exporter string
+		valid    bool
+	}{
+		{"otlp", false},
+		{otlpExperimental, true},
+		{"stdout", true},
+	}
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		cfg.Tracing.Exporter = tt.exporter
+		if err := cfg.validate(); (err == nil) != tt.valid {
+			t.Errorf("exporter %s: validate returned %v, want valid %v", tt.exporter, err, tt.valid)
+		}
+	}
+}
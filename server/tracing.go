@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tracerName is the instrumentation library of the spans of the server
+const tracerName = "github.com/vaibhav/assignment1/server"
+
+// tracer starts the spans of the server. It is a no-op until setupTracing installs a provider,
+// so the code tracing Mongo commands runs the same with tracing disabled.
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// setupTracing installs the tracer provider exporting the spans the way cfg says, and the W3C trace context propagation.
+// Shutting the provider down flushes the spans not exported yet.
+func setupTracing(cfg TracingConfig) (*sdktrace.TracerProvider, error) {
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case otlpExperimental:
+		zap.L().Warn("The OTLP exporter is experimental, its encoding follows OTLP/HTTP JSON but isn't the upstream exporter",
+			zap.String("endpoint", cfg.Endpoint))
+		exporter = newOTLPExporter(cfg.Endpoint)
+	case "stdout":
+		exporter = newJSONExporter(os.Stdout, nil)
+	case "file":
+		f, err := os.OpenFile(cfg.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("could not open the trace file: %w", err)
+		}
+		exporter = newJSONExporter(f, f)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+
+	// calls continuing a trace are recorded when their caller recorded it, the ratio only decides for the new traces
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider, nil
+}
+
+// metadataCarrier lets the propagators read the trace context of a call from its metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// startServerSpan starts the span of a call, as a child of the span of the caller when its metadata carries a trace context
+func startServerSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	service, method := splitMethodName(fullMethod)
+	return tracer().Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("grpc"),
+			semconv.RPCServiceKey.String(service),
+			semconv.RPCMethodKey.String(method),
+			semconv.NetPeerIPKey.String(peerAddress(ctx)),
+		))
+}
+
+// endServerSpan records the outcome of a call on its span and ends it
+func endServerSpan(span trace.Span, err error) {
+	st := status.Convert(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int64(int64(st.Code())))
+	if err != nil {
+		span.SetStatus(otelcodes.Error, st.Message())
+	}
+	span.End()
+}
+
+// tracingUnaryInterceptor traces every call, the spans of the MongoDB commands of the handler are its children
+func tracingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startServerSpan(ctx, info.FullMethod)
+	resp, err := handler(ctx, req)
+	endServerSpan(span, err)
+	return resp, err
+}
+
+// tracingStreamInterceptor is tracingUnaryInterceptor for the streaming calls
+func tracingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := startServerSpan(ss.Context(), info.FullMethod)
+	err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	endServerSpan(span, err)
+	return err
+}
+
+// traceMongoCommands returns a monitor giving every command a span, child of the span of the call it is sent for,
+// on top of what monitor does
+func traceMongoCommands(monitor *event.CommandMonitor) *event.CommandMonitor {
+	// the spans of the commands in flight, by request id: the driver only gives the context to Started
+	var spans sync.Map
+
+	end := func(requestID int64, failure string) {
+		value, ok := spans.LoadAndDelete(requestID)
+		if !ok {
+			return
+		}
+		span := value.(trace.Span)
+		if failure != "" {
+			span.SetStatus(otelcodes.Error, failure)
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			attrs := []attribute.KeyValue{
+				semconv.DBSystemMongoDB,
+				semconv.DBNameKey.String(e.DatabaseName),
+				semconv.DBOperationKey.String(e.CommandName),
+			}
+			// the first element of a command names it, its value is the collection for the CRUD commands
+			if elem, err := e.Command.IndexErr(0); err == nil {
+				if collection, ok := elem.Value().StringValueOK(); ok && elem.Key() == e.CommandName {
+					attrs = append(attrs, semconv.DBMongoDBCollectionKey.String(collection))
+				}
+			}
+			_, span := tracer().Start(ctx, "mongodb."+e.CommandName, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+			spans.Store(e.RequestID, span)
+			if monitor.Started != nil {
+				monitor.Started(ctx, e)
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			end(e.RequestID, "")
+			if monitor.Succeeded != nil {
+				monitor.Succeeded(ctx, e)
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			end(e.RequestID, e.Failure)
+			if monitor.Failed != nil {
+				monitor.Failed(ctx, e)
+			}
+		},
+	}
+}
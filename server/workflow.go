@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	blogpb "github.com/vaibhav/assignment1/proto"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusTransitions gives, for every status a blog can be moved to, the status it has to be in.
+// The workflow is linear: draft -> in_review -> published -> archived.
+var statusTransitions = map[string]string{
+	statusInReview:  statusDraft,
+	statusPublished: statusInReview,
+	statusArchived:  statusPublished,
+}
+
+var statusToProto = map[string]blogpb.Blog_Status{
+	statusDraft:     blogpb.Blog_DRAFT,
+	statusInReview:  blogpb.Blog_IN_REVIEW,
+	statusPublished: blogpb.Blog_PUBLISHED,
+	statusArchived:  blogpb.Blog_ARCHIVED,
+}
+
+var statusFromProto = map[blogpb.Blog_Status]string{
+	blogpb.Blog_DRAFT:     statusDraft,
+	blogpb.Blog_IN_REVIEW: statusInReview,
+	blogpb.Blog_PUBLISHED: statusPublished,
+	blogpb.Blog_ARCHIVED:  statusArchived,
+}
+
+func (s *BlogServiceServer) SubmitForReview(ctx context.Context, req *blogpb.SubmitForReviewReq) (*blogpb.SubmitForReviewRes, error) {
+	data, err := s.transition(ctx, req.GetId(), statusInReview)
+	if err != nil {
+		return nil, err
+	}
+	return &blogpb.SubmitForReviewRes{Blog: data.toProto()}, nil
+}
+
+// PublishBlog publishes an in review blog now, or schedules it when publish_at is in the future
+func (s *BlogServiceServer) PublishBlog(ctx context.Context, req *blogpb.PublishBlogReq) (*blogpb.PublishBlogRes, error) {
+	if req.GetPublishAt() != nil {
+		if err := req.GetPublishAt().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Invalid publish_at: %v", err))
+		}
+		if at := req.GetPublishAt().AsTime(); at.After(now()) {
+			return s.schedulePublish(ctx, req.GetId(), at)
+		}
+	}
+
+	data, err := s.transition(ctx, req.GetId(), statusPublished)
+	if err != nil {
+		return nil, err
+	}
+	return &blogpb.PublishBlogRes{Blog: data.toProto()}, nil
+}
+
+func (s *BlogServiceServer) ArchiveBlog(ctx context.Context, req *blogpb.ArchiveBlogReq) (*blogpb.ArchiveBlogRes, error) {
+	data, err := s.transition(ctx, req.GetId(), statusArchived)
+	if err != nil {
+		return nil, err
+	}
+	return &blogpb.ArchiveBlogRes{Blog: data.toProto()}, nil
+}
+
+// schedulePublish records the publication time of an in review blog, runScheduler publishes it when due
+func (s *BlogServiceServer) schedulePublish(ctx context.Context, id string, at time.Time) (*blogpb.PublishBlogRes, error) {
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
+	}
+
+	if err := s.checkBlogOwner(ctx, oid, store.Get); err != nil {
+		return nil, err
+	}
+
+	data, err := store.SchedulePublish(ctx, oid, at.UTC().Truncate(time.Millisecond), workflowEditor(ctx), now())
+	if err != nil {
+		return nil, storeError(err, fmt.Sprintf("Could not schedule the publication of blog %s", id))
+	}
+	return &blogpb.PublishBlogRes{Blog: data.toProto()}, nil
+}
+
+// workflowEditor is the editor recorded on the status changes: the caller, or the last editor of the blog
+// when authentication is disabled, the workflow calls naming nobody
+func workflowEditor(ctx context.Context) string {
+	if p := principalFromContext(ctx); p != nil {
+		return p.Subject
+	}
+	return ""
+}
+
+// transition moves the blog with this id to status to, from the only status that leads there
+func (s *BlogServiceServer) transition(ctx context.Context, id string, to string) (*BlogItem, error) {
+	store, err := s.storeOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, fmt.Sprintf("Could not convert to ObjectId: %v", err))
+	}
+
+	if err := s.checkBlogOwner(ctx, oid, store.Get); err != nil {
+		return nil, err
+	}
+
+	data, err := store.SetStatus(ctx, oid, statusTransitions[to], to, workflowEditor(ctx), now())
+	if err != nil {
+		return nil, storeError(err, fmt.Sprintf("Could not move blog %s to %s", id, to))
+	}
+	return data, nil
+}